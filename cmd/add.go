@@ -14,12 +14,71 @@ func handleAdd(svc task.Tasker) {
 		return
 	}
 
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: task-cli add <description>")
+	showHelp := func() {
+		fmt.Println("Usage: task-cli add <description> [--priority low|medium|high|urgent] [--due <date>] [--project <name>] [--parent <id>] [--repeat <rule>] [--estimate <duration>]")
+	}
+
+	args := cli.ParseArgs(os.Args[2:])
+	if len(args.Positional) < 1 {
+		showHelp()
 		return
 	}
 
-	id, err := svc.Add(task.Task{Description: os.Args[2]})
+	t := task.Task{Description: args.Positional[0]}
+	if v, ok := args.Flag("priority"); ok {
+		p, err := task.ParsePriority(v)
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+		t.Priority = p
+	}
+	if v, ok := args.Flag("due"); ok {
+		due, err := parseDate(svc, v)
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+		t.Due = due
+	}
+	if v, ok := args.Flag("project"); ok {
+		projectID, err := resolveProject(svc, v)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		t.ProjectID = projectID
+	}
+	if v, ok := args.Flag("parent"); ok {
+		parentID, err := resolveID(svc, v)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		t.ParentID = parentID
+	}
+	if v, ok := args.Flag("repeat"); ok {
+		r, err := task.ParseRecurrence(v)
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+		t.Recurrence = &r
+	}
+	if v, ok := args.Flag("estimate"); ok {
+		e, err := task.ParseEstimate(v)
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+		t.Estimate = e
+	}
+
+	id, err := svc.Add(t)
 	if err != nil {
 		fmt.Println(fmt.Errorf("failed add task to list: %w", err))
 		return
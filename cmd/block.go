@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// handleBlock adds or removes a "blocked by" dependency between two tasks
+func handleBlock(svc task.Tasker, block bool) {
+	command := "block"
+	if !block {
+		command = "unblock"
+	}
+	showHelp := func() {
+		fmt.Printf("Usage: task-cli %s <id> --on <other id>\n", command)
+	}
+
+	args := cli.ParseArgs(os.Args[2:])
+	on, ok := args.Flag("on")
+	if len(args.Positional) < 1 || !ok {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, args.Positional[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	onID, err := resolveID(svc, on)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if block {
+		err = svc.Block(id, onID)
+	} else {
+		err = svc.Unblock(id, onID)
+	}
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to %s task %d: %w", command, id, err))
+		return
+	}
+	if block {
+		fmt.Printf("Task %d is now blocked by task %d\n", id, onID)
+		return
+	}
+	fmt.Printf("Task %d is no longer blocked by task %d\n", id, onID)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/dateparse"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// parseDate reads a date typed at the shell such as "tomorrow" or "in 3 days"
+// relative to the service's clock
+func parseDate(svc task.Tasker, s string) (time.Time, error) {
+	return dateparse.Parse(s, svc.Now())
+}
+
+// formatDate hides the time of day when a date has none
+func formatDate(t time.Time) string {
+	t = t.Local()
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -1,30 +1,41 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
 
+	"github.com/ColinEge/task-cli/internal/cli"
 	"github.com/ColinEge/task-cli/internal/task"
 )
 
 func handleDelete(svc task.Tasker) {
-	if len(os.Args) < 3 {
-		fmt.Println("Usage: task-cli delete <id>")
+	args := cli.ParseArgs(os.Args[2:], "cascade")
+	if len(args.Positional) < 1 {
+		fmt.Println("Usage: task-cli delete <id> [--cascade]")
 		return
 	}
 
-	id, err := strconv.Atoi(os.Args[2])
+	id, err := resolveIDToDelete(svc, args.Positional[0])
 	if err != nil {
-		fmt.Println("Usage: task-cli delete <id>")
+		fmt.Println(err)
 		return
 	}
 
-	err = svc.Delete(int64(id))
+	var opts []task.MutationOption
+	if args.Bool("cascade") {
+		opts = append(opts, task.WithCascade())
+	}
+
+	err = svc.Delete(id, opts...)
+	if errors.Is(err, task.ErrHasChildren) {
+		fmt.Println(fmt.Errorf("failed delete task %d: %w, use --cascade to delete them too", id, err))
+		return
+	}
 	if err != nil {
 		fmt.Println(fmt.Errorf("failed delete task %d: %w", id, err))
 		return
 	}
-	fmt.Printf("Task deleted successfully (ID: %d)\n", id)
+	fmt.Printf("Task moved to trash (ID: %d)\n", id)
 
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleDue(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli due <id> <date|none>")
+	}
+
+	if len(os.Args) < 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var due time.Time
+	if !strings.EqualFold(os.Args[3], "none") {
+		due, err = parseDate(svc, os.Args[3])
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+	}
+
+	if err := svc.SetDue(id, due); err != nil {
+		fmt.Println(fmt.Errorf("failed to set due date of task %d: %w", id, err))
+		return
+	}
+	if due.IsZero() {
+		fmt.Printf("Task due date cleared successfully (ID: %d)\n", id)
+		return
+	}
+	fmt.Printf("Task due %s (ID: %d)\n", formatDate(due), id)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleEstimate(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli estimate <id> <duration, e.g. 30m or 2h|none>")
+	}
+
+	if len(os.Args) != 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var estimate task.Estimate
+	if !strings.EqualFold(os.Args[3], "none") {
+		estimate, err = task.ParseEstimate(os.Args[3])
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+	}
+
+	if err := svc.SetEstimate(id, estimate); err != nil {
+		fmt.Println(fmt.Errorf("failed to set estimate of task %d: %w", id, err))
+		return
+	}
+	if estimate == 0 {
+		fmt.Printf("Task estimate cleared (ID: %d)\n", id)
+		return
+	}
+	fmt.Printf("Task estimated at %s (ID: %d)\n", estimate, id)
+}
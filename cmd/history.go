@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleHistory(svc task.Tasker) {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: task-cli history <id>")
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	history, err := svc.History(id)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to get history of task %d: %w", id, err))
+		return
+	}
+	fmt.Print(formatHistory(history, svc.Now()))
+}
+
+func formatHistory(history []task.Change, now time.Time) string {
+	b := strings.Builder{}
+	const timeLayout = "2006-01-02 15:04"
+
+	for _, c := range history {
+		b.WriteString(c.At.Local().Format(timeLayout))
+		b.WriteString("  ")
+		switch {
+		case c.Field == task.FieldStatus && c.From == "":
+			fmt.Fprintf(&b, "created as %s", c.To)
+		case c.Field == task.FieldStatus:
+			fmt.Fprintf(&b, "status %s -> %s", c.From, c.To)
+		default:
+			fmt.Fprintf(&b, "%s %q -> %q", c.Field, c.From, c.To)
+		}
+		b.WriteRune('\n')
+	}
+
+	spans := task.TimeInStatus(history, now)
+	if len(spans) == 0 {
+		return b.String()
+	}
+
+	// Line the durations up after the longest status name
+	longestStatus := 0
+	for _, span := range spans {
+		if len(span.Status) > longestStatus {
+			longestStatus = len(span.Status)
+		}
+	}
+	b.WriteString("\nTime in status:\n")
+	for _, span := range spans {
+		b.WriteString("  ")
+		b.WriteString(span.Status)
+		for i := 0; i < longestStatus+2-len(span.Status); i++ {
+			b.WriteRune(' ')
+		}
+		b.WriteString(span.Duration.Round(time.Second).String())
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
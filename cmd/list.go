@@ -1,80 +1,353 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/config"
+	"github.com/ColinEge/task-cli/internal/query"
 	"github.com/ColinEge/task-cli/internal/task"
 )
 
-func handleList(svc task.Tasker) {
+func handleList(svc task.Tasker, cfg config.Config) {
+	raw := os.Args[2:]
+	if len(raw) > 0 && strings.HasPrefix(raw[0], "@") {
+		v, err := cfg.View(raw[0][1:])
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+			return
+		}
+		raw = viewArgs(v, raw[1:])
+	}
+	listTasks(svc, raw)
+}
+
+// listFormats are the output formats of list, table is the default
+var listFormats = []string{"table", "json", "ids"}
+
+func listTasks(svc task.Tasker, raw []string) {
 	showHelp := func() {
-		fmt.Println("Usage: task-cli list [|todo|in-progress|done]")
+		fmt.Println("Usage: task-cli list [@view] [|<status>|overdue|today|upcoming|blocked|ready] [+tag] [-tag] [--priority low|medium|high|urgent] [--project <name|none>] [--group project] [--sort <key[:desc],...>] [--limit <n>] [--offset <n>|--after <id>] [--format table|json|ids] ['<query>']")
 	}
 
-	if len(os.Args) < 2 {
-		showHelp()
+	args := cli.ParseArgs(raw)
+	opts, err := listOptions(svc, args)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+		var qerr *query.Error
+		if errors.As(err, &qerr) {
+			fmt.Println(qerr.Caret())
+		}
+		if errors.Is(err, errListUsage) {
+			showHelp()
+		}
 		return
 	}
+	group, _ := args.Flag("group")
+	format, _ := args.Flag("format")
+	workflow := svc.Workflow()
 
-	var status *task.Status = nil
+	// Ask for one extra task to know whether there is another page
+	limit := opts.Limit
+	if limit > 0 {
+		opts.Limit++
+	}
+	list, err := svc.List(opts)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+		return
+	}
+	more := limit > 0 && len(list) > limit
+	if more {
+		list = list[:limit]
+	}
 
-	if len(os.Args) > 2 {
-		switch strings.ToLower(os.Args[2]) {
-		case "todo":
-			s := task.StatusTodo
-			status = &s
-		case "in-progress":
-			s := task.StatusInProgress
-			status = &s
-		case "done":
-			s := task.StatusDone
-			status = &s
+	switch format {
+	case "json":
+		out := make([]taskJSON, len(list))
+		for i, t := range list {
+			out[i] = newTaskJSON(t, workflow)
+		}
+		js, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+			return
+		}
+		fmt.Println(string(js))
+		return
+	case "ids":
+		for _, t := range list {
+			fmt.Println(t.Id)
 		}
+		return
+	}
+	// Subtasks are only pulled under their parents when the order is the
+	// default one and the whole list is shown, so --sort and paging are kept
+	tree := len(opts.Sort) == 0 && limit == 0 && opts.Offset == 0 && opts.After == 0
+	if more {
+		defer fmt.Printf("\nMore tasks, continue with --after %d\n", list[len(list)-1].Id)
 	}
 
-	list, err := svc.List(status)
-	if err != nil {
-		fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+	if group == "project" {
+		projects, err := svc.Projects()
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to list projects: %w", err))
+			return
+		}
+		// Completion counts cover every task in a project, not just the listed ones
+		all, err := svc.List(task.ListOptions{})
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+			return
+		}
+		fmt.Print(formatByProject(list, all, projects, workflow, tree))
 		return
 	}
-	b := strings.Builder{}
-	b.WriteString("Tasks:\n\n")
+	fmt.Print(formatTasks(list, workflow, tree))
+}
 
-	fmt.Print(formatTasks(list))
+// errListUsage is a list argument that can't be understood, the usage is
+// shown with it
+var errListUsage = errors.New("invalid list argument")
+
+// listOptions reads list arguments into the options for svc.List. Saving a
+// view runs it too, so a view that can't be listed is never saved
+func listOptions(svc task.Tasker, args cli.Args) (task.ListOptions, error) {
+	opts := task.ListOptions{}
+	workflow := svc.Workflow()
+
+	// Anything that isn't a tag, status or view word is part of a query
+	var queryParts []string
+	for _, arg := range args.Positional {
+		if strings.ContainsAny(arg, " :=<>~()\"'") {
+			queryParts = append(queryParts, arg)
+			continue
+		}
+		if tag, ok := strings.CutPrefix(arg, "+"); ok {
+			opts.Tags = append(opts.Tags, tag)
+			continue
+		}
+		if tag, ok := strings.CutPrefix(arg, "-"); ok {
+			opts.ExcludeTags = append(opts.ExcludeTags, tag)
+			continue
+		}
+		if status, err := workflow.Parse(arg); err == nil {
+			opts.Status = &status
+			continue
+		}
+		switch strings.ToLower(arg) {
+		case "overdue":
+			opts.View = task.ViewOverdue
+		case "today":
+			opts.View = task.ViewToday
+		case "upcoming":
+			opts.View = task.ViewUpcoming
+		case "blocked":
+			opts.View = task.ViewBlocked
+		case "ready":
+			opts.View = task.ViewReady
+		default:
+			queryParts = append(queryParts, arg)
+		}
+	}
+	// The words are joined back as they were typed, side by side comparisons
+	// already mean "and" to the parser
+	if len(queryParts) > 0 {
+		filter, err := compileQuery(svc, strings.Join(queryParts, " "))
+		if err != nil {
+			return task.ListOptions{}, err
+		}
+		opts.Filter = filter
+	}
+	if v, ok := args.Flag("priority"); ok {
+		p, err := task.ParsePriority(v)
+		if err != nil {
+			return task.ListOptions{}, fmt.Errorf("%w: %w", errListUsage, err)
+		}
+		opts.Priority = &p
+	}
+	if v, ok := args.Flag("project"); ok {
+		projectID, err := resolveProject(svc, v)
+		if err != nil {
+			return task.ListOptions{}, err
+		}
+		opts.ProjectID = &projectID
+	}
+	if v, ok := args.Flag("sort"); ok {
+		keys, err := task.ParseSort(v)
+		if err != nil {
+			return task.ListOptions{}, fmt.Errorf("%w: %w", errListUsage, err)
+		}
+		opts.Sort = keys
+	}
+	for name, value := range map[string]*int{"limit": &opts.Limit, "offset": &opts.Offset} {
+		if v, ok := args.Flag(name); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return task.ListOptions{}, fmt.Errorf("%w --%s %q", errListUsage, name, v)
+			}
+			*value = n
+		}
+	}
+	if v, ok := args.Flag("after"); ok {
+		after, err := strconv.Atoi(v)
+		if err != nil {
+			return task.ListOptions{}, fmt.Errorf("%w --after %q", errListUsage, v)
+		}
+		opts.After = int64(after)
+	}
+	if group, ok := args.Flag("group"); ok && group != "project" {
+		return task.ListOptions{}, fmt.Errorf("%w --group %q", errListUsage, group)
+	}
+	if format, ok := args.Flag("format"); ok && !slices.Contains(listFormats, format) {
+		return task.ListOptions{}, fmt.Errorf("%w --format %q", errListUsage, format)
+	}
+	return opts, nil
+}
+
+func compileQuery(svc task.Tasker, src string) (*query.Query, error) {
+	projects, err := svc.Projects()
+	if err != nil {
+		return nil, err
+	}
+	return query.Compile(src, query.Context{
+		Now:      svc.Now(),
+		Workflow: svc.Workflow(),
+		Schema:   svc.Schema(),
+		Projects: projects,
+	})
+}
+
+// formatByProject writes a section per project with its completion count over
+// all of its tasks, tasks without a project come last
+func formatByProject(tasks, all []task.Task, projects []task.Project, workflow task.Workflow, tree bool) string {
+	done, total := completionCounts(all)
+	groups := map[int64][]task.Task{}
+	for _, t := range tasks {
+		groups[t.ProjectID] = append(groups[t.ProjectID], t)
+	}
+
+	b := strings.Builder{}
+	writeGroup := func(name string, id int64) {
+		if len(groups[id]) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteRune('\n')
+		}
+		fmt.Fprintf(&b, "%s (%d/%d done)\n", name, done[id], total[id])
+		b.WriteString(formatTasks(groups[id], workflow, tree))
+	}
+	for _, p := range projects {
+		writeGroup(p.Name, p.Id)
+	}
+	writeGroup("No project", 0)
+	return b.String()
 }
 
-func formatTasks(tasks []task.Task) string {
+// formatTasks writes one row per task, with tree set subtasks are indented
+// under their parents, otherwise the rows keep the order they were given in
+func formatTasks(tasks []task.Task, workflow task.Workflow, tree bool) string {
 	b := strings.Builder{}
 
 	// Work out the length of each column to make tabular format
 	longestDesc := 0
-	const statusDefaultLength = 4
-	const statusInProgressLength = 11
 	statusLength := 0
-	for _, t := range tasks {
-		descLen := len(t.Description)
+	const priorityLength = 6
+	rows := make([]treeRow, len(tasks))
+	if tree {
+		rows = treeOrder(tasks)
+	} else {
+		for i, t := range tasks {
+			rows[i] = treeRow{task: t, label: t.Description}
+		}
+	}
+	for _, row := range rows {
+		t := row.task
+		descLen := utf8.RuneCountInString(row.label)
 		if descLen > longestDesc {
 			longestDesc = descLen
 		}
-		if statusLength != statusInProgressLength {
-			if t.Status == task.StatusInProgress {
-				statusLength = statusInProgressLength
-			} else {
-				statusLength = statusDefaultLength
-			}
+		if l := len(workflow.Name(t.Status)); l > statusLength {
+			statusLength = l
 		}
 	}
 
 	// Now write the tasks out
-	for _, t := range tasks {
-		b.WriteString(t.Description)
-		for i := 0; i < longestDesc+2-len(t.Description); i++ {
+	for _, row := range rows {
+		t := row.task
+		b.WriteString(row.label)
+		for i := 0; i < longestDesc+2-utf8.RuneCountInString(row.label); i++ {
+			b.WriteRune(' ')
+		}
+		b.WriteString(workflow.Name(t.Status))
+		for i := 0; i < statusLength+2-len(workflow.Name(t.Status)); i++ {
 			b.WriteRune(' ')
 		}
-		b.WriteString(t.Status.String())
+		b.WriteString(t.Priority.String())
+		if !t.Due.IsZero() {
+			for i := 0; i < priorityLength+2-len(t.Priority.String()); i++ {
+				b.WriteRune(' ')
+			}
+			b.WriteString("due ")
+			b.WriteString(formatDate(t.Due))
+		}
+		if t.Recurrence != nil {
+			b.WriteString("  repeats ")
+			b.WriteString(t.Recurrence.String())
+		}
+		if len(t.Tags) > 0 {
+			b.WriteString("  +")
+			b.WriteString(strings.Join(t.Tags, " +"))
+		}
 		b.WriteRune('\n')
 	}
 	return b.String()
 }
+
+type treeRow struct {
+	task  task.Task
+	label string
+}
+
+// treeOrder puts subtasks straight after their parent, indented one level
+// deeper. Subtasks whose parent is not in the list are shown at the top level
+func treeOrder(tasks []task.Task) []treeRow {
+	listed := map[int64]bool{}
+	children := map[int64][]task.Task{}
+	for _, t := range tasks {
+		listed[t.Id] = true
+	}
+	var roots []task.Task
+	for _, t := range tasks {
+		if t.ParentID != 0 && listed[t.ParentID] {
+			children[t.ParentID] = append(children[t.ParentID], t)
+			continue
+		}
+		roots = append(roots, t)
+	}
+
+	rows := make([]treeRow, 0, len(tasks))
+	var walk func(t task.Task, depth int)
+	walk = func(t task.Task, depth int) {
+		label := t.Description
+		if depth > 0 {
+			label = strings.Repeat("  ", depth-1) + "└ " + label
+		}
+		rows = append(rows, treeRow{task: t, label: label})
+		for _, child := range children[t.Id] {
+			walk(child, depth+1)
+		}
+	}
+	for _, t := range roots {
+		walk(t, 0)
+	}
+	return rows
+}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/config"
 	"github.com/ColinEge/task-cli/internal/task"
 )
 
@@ -14,7 +17,29 @@ func main() {
 		return
 	}
 
-	svc := task.NewTaskService(task.WithSavePath("tasks.json"), task.WithTimeFunction(time.Now))
+	cfg, err := config.Load(config.DefaultPath)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to load config: %w", err))
+		return
+	}
+	workflow, err := cfg.TaskWorkflow()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to load config: %w", err))
+		return
+	}
+	schema, err := cfg.TaskSchema()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to load config: %w", err))
+		return
+	}
+
+	svc := task.NewTaskService(
+		task.WithSavePath("tasks.json"),
+		task.WithTimeFunction(time.Now),
+		task.WithWorkflow(workflow),
+		task.WithSchema(schema),
+	)
+	defer printWarnings(svc)
 
 	switch os.Args[1] {
 	case "add":
@@ -23,11 +48,72 @@ func main() {
 		handleUpdate(svc)
 	case "delete":
 		handleDelete(svc)
-	case "mark-in-progress":
-		handleMark(svc, task.StatusInProgress)
-	case "mark-done":
-		handleMark(svc, task.StatusDone)
+	case "prioritize":
+		handlePrioritize(svc)
+	case "due":
+		handleDue(svc)
+	case "tag":
+		handleTag(svc)
+	case "tags":
+		handleTags(svc)
+	case "repeat":
+		handleRepeat(svc)
+	case "note":
+		handleNote(svc)
+	case "show":
+		handleShow(svc)
+	case "set":
+		handleSet(svc)
+	case "estimate":
+		handleEstimate(svc)
+	case "start":
+		handleStart(svc)
+	case "stop":
+		handleStop(svc)
+	case "report":
+		handleReport(svc)
+	case "block":
+		handleBlock(svc, true)
+	case "unblock":
+		handleBlock(svc, false)
+	case "project":
+		handleProject(svc)
+	case "search":
+		handleSearch(svc)
 	case "list":
-		handleList(svc)
+		handleList(svc, cfg)
+	case "view":
+		handleView(svc, cfg)
+	case "trash":
+		handleTrash(svc)
+	case "restore":
+		handleRestore(svc)
+	case "purge":
+		handlePurge(svc)
+	case "history":
+		handleHistory(svc)
+	case "undo":
+		handleUndo(svc)
+	case "redo":
+		handleRedo(svc)
+	default:
+		// mark-<status> for every status in the workflow
+		if name, ok := strings.CutPrefix(os.Args[1], "mark-"); ok {
+			status, err := workflow.Parse(name)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			handleMark(svc, status)
+		}
+	}
+}
+
+// printWarnings tells the user about problems the store recovered from, like
+// tasks read from the backup because tasks.json was corrupt. They go to
+// stderr so output meant for scripts, such as --format ids, stays clean
+func printWarnings(svc task.Tasker) {
+	for _, w := range svc.Warnings() {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("warning: %w", w))
 	}
 }
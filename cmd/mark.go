@@ -1,38 +1,59 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
 
+	"github.com/ColinEge/task-cli/internal/cli"
 	"github.com/ColinEge/task-cli/internal/task"
 )
 
 func handleMark(svc task.Tasker, status task.Status) {
+	name := svc.Workflow().Name(status)
 	showHelp := func() {
 		switch status {
 		case task.StatusInProgress:
-			fmt.Println("Usage: task-cli mark-in-progress <id>")
+			fmt.Println("Usage: task-cli mark-in-progress <id> [--force]")
 		case task.StatusDone:
-			fmt.Println("Usage: task-cli mark-in-done <id>")
+			fmt.Println("Usage: task-cli mark-done <id> [--cascade]")
+		default:
+			fmt.Printf("Usage: task-cli mark-%s <id>\n", name)
 		}
 	}
 
-	if len(os.Args) < 3 {
+	args := cli.ParseArgs(os.Args[2:], "cascade", "force")
+	if len(args.Positional) < 1 {
 		showHelp()
 		return
 	}
 
-	id, err := strconv.Atoi(os.Args[2])
+	id, err := resolveID(svc, args.Positional[0])
 	if err != nil {
-		showHelp()
+		fmt.Println(err)
 		return
 	}
 
-	err = svc.Mark(int64(id), status)
+	var opts []task.MutationOption
+	if args.Bool("cascade") {
+		opts = append(opts, task.WithCascade())
+	}
+	if args.Bool("force") {
+		opts = append(opts, task.WithForce())
+	}
+
+	err = svc.Mark(id, status, opts...)
+	if errors.Is(err, task.ErrOpenChildren) {
+		fmt.Println(fmt.Errorf("failed to mark task as %s: %w, use --cascade to mark them too", name, err))
+		return
+	}
+	if errors.Is(err, task.ErrBlocked) {
+		fmt.Println(fmt.Errorf("failed to mark task as %s: %w, use --force to start it anyway", name, err))
+		return
+	}
 	if err != nil {
-		fmt.Println(fmt.Errorf("failed to mark task as %s: %w", status.String(), err))
+		fmt.Println(fmt.Errorf("failed to mark task as %s: %w", name, err))
 		return
 	}
-	fmt.Printf("Task marked as %s successfully (ID: %d)\n", status.String(), id)
+	fmt.Printf("Task marked as %s successfully (ID: %d)\n", name, id)
 }
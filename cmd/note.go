@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// handleNote appends a note to a task. Without text, or with "-", the note is
+// read from stdin so it can span several lines
+func handleNote(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println(`Usage: task-cli note <id> ["text"|-]`)
+	}
+
+	if len(os.Args) < 3 || len(os.Args) > 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	text := ""
+	if len(os.Args) == 4 && os.Args[3] != "-" {
+		text = os.Args[3]
+	} else {
+		if isTerminal(os.Stdin) {
+			fmt.Println("Enter the note, finish with Ctrl-D:")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to read note: %w", err))
+			return
+		}
+		text = string(data)
+	}
+
+	if err := svc.AddNote(id, text); err != nil {
+		fmt.Println(fmt.Errorf("failed to add note to task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Note added successfully (ID: %d)\n", id)
+}
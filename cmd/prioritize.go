@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handlePrioritize(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli prioritize <id> <low|medium|high|urgent>")
+	}
+
+	if len(os.Args) < 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	priority, err := task.ParsePriority(os.Args[3])
+	if err != nil {
+		fmt.Println(err)
+		showHelp()
+		return
+	}
+
+	if err := svc.Prioritize(id, priority); err != nil {
+		fmt.Println(fmt.Errorf("failed to prioritize task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Task priority set to %s successfully (ID: %d)\n", priority.String(), id)
+}
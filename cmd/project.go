@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleProject(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli project <add <name> [description]|list|archive <name>|assign <id> <name|none>>")
+	}
+
+	if len(os.Args) < 3 {
+		showHelp()
+		return
+	}
+
+	switch os.Args[2] {
+	case "add":
+		if len(os.Args) < 4 {
+			showHelp()
+			return
+		}
+		p := task.Project{Name: os.Args[3]}
+		if len(os.Args) > 4 {
+			p.Description = strings.Join(os.Args[4:], " ")
+		}
+		id, err := svc.AddProject(p)
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to add project: %w", err))
+			return
+		}
+		fmt.Printf("Project added successfully (ID: %d)\n", id)
+	case "list":
+		listProjects(svc)
+	case "archive":
+		if len(os.Args) < 4 {
+			showHelp()
+			return
+		}
+		if err := svc.ArchiveProject(os.Args[3]); err != nil {
+			fmt.Println(fmt.Errorf("failed to archive project: %w", err))
+			return
+		}
+		fmt.Printf("Project archived successfully (%s)\n", os.Args[3])
+	case "assign":
+		if len(os.Args) < 5 {
+			showHelp()
+			return
+		}
+		id, err := resolveID(svc, os.Args[3])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		projectID, err := resolveProject(svc, os.Args[4])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := svc.AssignProject(id, projectID); err != nil {
+			fmt.Println(fmt.Errorf("failed to assign project: %w", err))
+			return
+		}
+		fmt.Printf("Task moved to project %s successfully (ID: %d)\n", strings.ToLower(os.Args[4]), id)
+	default:
+		showHelp()
+	}
+}
+
+func listProjects(svc task.Tasker) {
+	projects, err := svc.Projects()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to list projects: %w", err))
+		return
+	}
+	if len(projects) == 0 {
+		fmt.Println("No projects yet")
+		return
+	}
+	tasks, err := svc.List(task.ListOptions{})
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+		return
+	}
+	done, total := completionCounts(tasks)
+
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%d/%d done\t%s\n", p.Name, p.Status.String(), done[p.Id], total[p.Id], p.Description)
+	}
+	w.Flush()
+	fmt.Print(b.String())
+}
+
+// completionCounts counts done and total tasks per project id
+func completionCounts(tasks []task.Task) (done, total map[int64]int) {
+	done, total = map[int64]int{}, map[int64]int{}
+	for _, t := range tasks {
+		total[t.ProjectID]++
+		if t.Status == task.StatusDone {
+			done[t.ProjectID]++
+		}
+	}
+	return done, total
+}
+
+// resolveProject turns a --project value into a project id, "none" meaning
+// tasks without a project
+func resolveProject(svc task.Tasker, name string) (int64, error) {
+	if strings.EqualFold(name, "none") {
+		return 0, nil
+	}
+	p, err := svc.FindProject(name)
+	if err != nil {
+		return 0, err
+	}
+	return p.Id, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleRepeat(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println(`Usage: task-cli repeat <id> <daily|weekly [mon,thu]|monthly [day]|every <n> days|none>`)
+	}
+
+	if len(os.Args) < 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var rule *task.Recurrence
+	if text := strings.Join(os.Args[3:], " "); !strings.EqualFold(text, "none") {
+		r, err := task.ParseRecurrence(text)
+		if err != nil {
+			fmt.Println(err)
+			showHelp()
+			return
+		}
+		rule = &r
+	}
+
+	if err := svc.SetRecurrence(id, rule); err != nil {
+		fmt.Println(fmt.Errorf("failed to set recurrence of task %d: %w", id, err))
+		return
+	}
+	if rule == nil {
+		fmt.Printf("Task no longer repeats (ID: %d)\n", id)
+		return
+	}
+	fmt.Printf("Task repeats %s (ID: %d)\n", rule.String(), id)
+}
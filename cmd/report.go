@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleReport(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli report <time|estimates>")
+	}
+
+	if len(os.Args) != 3 {
+		showHelp()
+		return
+	}
+
+	switch os.Args[2] {
+	case "time":
+		reportTime(svc)
+	case "estimates":
+		reportEstimates(svc)
+	default:
+		showHelp()
+	}
+}
+
+func reportTime(svc task.Tasker) {
+	report, err := svc.TimeReport()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to build time report: %w", err))
+		return
+	}
+	if len(report.ByTask) == 0 {
+		fmt.Println("No time tracked")
+		return
+	}
+
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "By task")
+	for _, t := range report.ByTask {
+		running := ""
+		if t.Task.Running() {
+			running = "running"
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%s\t%s\n", t.Task.Id, t.Task.Description, formatDuration(t.Duration), running)
+	}
+
+	if len(report.ByTag) > 0 {
+		fmt.Fprintln(w, "\nBy tag")
+		tags := slices.Sorted(maps.Keys(report.ByTag))
+		for _, tag := range tags {
+			fmt.Fprintf(w, "  +%s\t%s\n", tag, formatDuration(report.ByTag[tag]))
+		}
+	}
+
+	fmt.Fprintln(w, "\nBy day")
+	for _, day := range slices.Sorted(maps.Keys(report.ByDay)) {
+		fmt.Fprintf(w, "  %s\t%s\n", day, formatDuration(report.ByDay[day]))
+	}
+	fmt.Fprintf(w, "\nTotal\t%s\n", formatDuration(report.Total))
+	w.Flush()
+	fmt.Print(b.String())
+}
+
+// formatDuration rounds to the minute and drops the trailing seconds, e.g.
+// "1h30m" or "45m"
+func formatDuration(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	if s == "" {
+		return "0m"
+	}
+	return s
+}
+
+func reportEstimates(svc task.Tasker) {
+	report, err := svc.EstimateReport()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to build estimate report: %w", err))
+		return
+	}
+	if len(report.Tasks) == 0 {
+		fmt.Println("No finished tasks with an estimate")
+		return
+	}
+
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDescription\tEstimate\tActual\tRatio")
+	for _, c := range report.Tasks {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.2f\n", c.Task.Id, c.Task.Description, formatDuration(c.Estimate), formatDuration(c.Actual), c.Ratio())
+	}
+	fmt.Fprintf(w, "\tTotal\t%s\t%s\t%.2f\n", formatDuration(report.Estimated), formatDuration(report.Actual), report.Ratio())
+	w.Flush()
+	fmt.Print(b.String())
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/fuzzy"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+var (
+	errNoMatch    = errors.New("no task matches")
+	errAmbiguous  = errors.New("several tasks match")
+	errLooseMatch = errors.New("task only loosely matches")
+)
+
+// maxChoices caps how many matches are offered or listed in an error
+const maxChoices = 9
+
+// resolveID turns an argument into a task id. Numbers are always ids,
+// anything else is matched against the descriptions of tasks not in the trash
+func resolveID(svc task.Tasker, arg string) (int64, error) {
+	return resolve(arg, false, func() ([]task.Task, error) { return svc.List(task.ListOptions{}) })
+}
+
+// resolveIDToDelete is resolveID for commands that destroy tasks, a single
+// task that doesn't contain the argument as written must be confirmed first
+func resolveIDToDelete(svc task.Tasker, arg string) (int64, error) {
+	return resolve(arg, true, func() ([]task.Task, error) { return svc.List(task.ListOptions{}) })
+}
+
+// resolveTrashedID is resolveID for tasks in the trash
+func resolveTrashedID(svc task.Tasker, arg string) (int64, error) {
+	return resolve(arg, false, svc.Trash)
+}
+
+func resolve(arg string, confirmLoose bool, candidates func() ([]task.Task, error)) (int64, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return int64(id), nil
+	}
+	if strings.TrimSpace(arg) == "" {
+		return 0, fmt.Errorf("%w an empty description", errNoMatch)
+	}
+
+	tasks, err := candidates()
+	if err != nil {
+		return 0, err
+	}
+	descriptions := make([]string, len(tasks))
+	for i, t := range tasks {
+		descriptions[i] = t.Description
+	}
+	matches := fuzzy.Candidates(arg, descriptions)
+	leftOut := max(len(matches)-maxChoices, 0)
+	matches = matches[:len(matches)-leftOut]
+
+	interactive := isTerminal(os.Stdin) && isTerminal(os.Stdout)
+	switch {
+	case len(matches) == 0:
+		return 0, fmt.Errorf("%w %q", errNoMatch, arg)
+	case len(matches) == 1:
+		t := tasks[matches[0].Index]
+		if !confirmLoose || fuzzy.Contains(arg, t.Description) {
+			return t.Id, nil
+		}
+		if !interactive {
+			return 0, fmt.Errorf("%w %q: %d %q, use its id instead", errLooseMatch, arg, t.Id, t.Description)
+		}
+		return confirm(arg, t)
+	}
+
+	matched := make([]task.Task, len(matches))
+	for i, m := range matches {
+		matched[i] = tasks[m.Index]
+	}
+	if !interactive {
+		names := make([]string, len(matched))
+		for i, t := range matched {
+			names[i] = fmt.Sprintf("%d %q", t.Id, t.Description)
+		}
+		if leftOut > 0 {
+			names = append(names, fmt.Sprintf("and %d more, narrow the pattern to see them", leftOut))
+		}
+		return 0, fmt.Errorf("%w %q, use an id instead: %s", errAmbiguous, arg, strings.Join(names, ", "))
+	}
+	return choose(arg, matched, leftOut)
+}
+
+// confirm asks whether a loosely matching task was the one meant
+func confirm(arg string, t task.Task) (int64, error) {
+	fmt.Printf("%q only loosely matches %q (ID: %d), use it? [y/N]: ", arg, t.Description, t.Id)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+		return 0, fmt.Errorf("%w %q, not confirmed", errLooseMatch, arg)
+	}
+	return t.Id, nil
+}
+
+// choose asks which of several matching tasks was meant, leftOut is how many
+// more matched than are offered
+func choose(arg string, matched []task.Task, leftOut int) (int64, error) {
+	fmt.Printf("Several tasks match %q:\n", arg)
+	for i, t := range matched {
+		fmt.Printf("  %d) %s (ID: %d)\n", i+1, t.Description, t.Id)
+	}
+	if leftOut > 0 {
+		fmt.Printf("  ...and %d more, use a narrower pattern if the task isn't listed\n", leftOut)
+	}
+	fmt.Printf("Choose a task [1-%d]: ", len(matched))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Println()
+		return 0, fmt.Errorf("%w %q, no task chosen", errAmbiguous, arg)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(matched) {
+		return 0, fmt.Errorf("%w %q, no task chosen", errAmbiguous, arg)
+	}
+	return matched[n-1].Id, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleSearch(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli search <terms...> [--limit <n>] [--reindex]")
+	}
+
+	args := cli.ParseArgs(os.Args[2:], "reindex")
+	if args.Bool("reindex") {
+		if err := svc.Reindex(); err != nil {
+			fmt.Println(fmt.Errorf("failed to rebuild search index: %w", err))
+			return
+		}
+		if len(args.Positional) == 0 {
+			fmt.Println("Search index rebuilt")
+			return
+		}
+	}
+	if len(args.Positional) == 0 {
+		showHelp()
+		return
+	}
+
+	limit := 0
+	if v, ok := args.Flag("limit"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			showHelp()
+			return
+		}
+		limit = n
+	}
+
+	query := strings.Join(args.Positional, " ")
+	results, err := svc.Search(query, limit)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to search tasks: %w", err))
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching tasks")
+		return
+	}
+	fmt.Print(formatSearchResults(results, task.Tokenize(query), svc.Workflow(), isTerminal(os.Stdout)))
+}
+
+// formatSearchResults lists each match with the notes that matched under it,
+// matched words are highlighted when writing to a terminal
+func formatSearchResults(results []task.SearchResult, terms []string, workflow task.Workflow, color bool) string {
+	highlight := func(s string) string {
+		if !color {
+			return s
+		}
+		return task.Highlight(s, terms, "\033[1;33m", "\033[0m")
+	}
+	matches := func(s string) bool {
+		return slices.ContainsFunc(task.Tokenize(s), func(word string) bool {
+			return slices.ContainsFunc(terms, func(q string) bool { return strings.HasPrefix(word, q) })
+		})
+	}
+
+	b := strings.Builder{}
+	for _, r := range results {
+		fmt.Fprintf(&b, "%d  %s  (%s)\n", r.Task.Id, highlight(r.Task.Description), workflow.Name(r.Task.Status))
+		for _, n := range r.Task.Notes {
+			for _, line := range strings.Split(n.Text, "\n") {
+				if matches(line) {
+					fmt.Fprintf(&b, "     %s\n", highlight(line))
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
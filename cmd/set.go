@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// handleSet sets custom fields declared in the config, "field=" clears one
+func handleSet(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli set <id> <field=value>...")
+		if fields := svc.Schema().Fields(); len(fields) > 0 {
+			fmt.Println("\nFields:")
+			for _, f := range fields {
+				if f.Type == task.TypeEnum {
+					fmt.Printf("  %s (%s)\n", f.Name, strings.Join(f.Values, ", "))
+					continue
+				}
+				fmt.Printf("  %s (%s)\n", f.Name, f.Type)
+			}
+		}
+	}
+
+	if len(os.Args) < 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	values := map[string]string{}
+	for _, arg := range os.Args[3:] {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			showHelp()
+			return
+		}
+		values[name] = value
+	}
+
+	if err := svc.SetFields(id, values); err != nil {
+		fmt.Println(fmt.Errorf("failed to set fields on task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Task updated successfully (ID: %d)\n", id)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleShow(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli show <id> [--json]")
+	}
+
+	args := cli.ParseArgs(os.Args[2:], "json")
+	if len(args.Positional) != 1 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, args.Positional[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	t, err := svc.Get(id)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to show task %d: %w", id, err))
+		return
+	}
+
+	if args.Bool("json") {
+		data, err := json.MarshalIndent(newTaskJSON(t, svc.Workflow()), "", "  ")
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to show task %d: %w", id, err))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	project := ""
+	if t.ProjectID != 0 {
+		projects, err := svc.Projects()
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to show task %d: %w", id, err))
+			return
+		}
+		for _, p := range projects {
+			if p.Id == t.ProjectID {
+				project = p.Name
+			}
+		}
+	}
+	fmt.Print(formatTask(t, svc.Workflow(), svc.Schema(), project, svc.Now()))
+}
+
+// formatTask lays a task out as a block of labelled fields, leaving out the
+// ones that are not set
+func formatTask(t task.Task, workflow task.Workflow, schema task.Schema, project string, now time.Time) string {
+	const timeLayout = "2006-01-02 15:04"
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", label, value)
+		}
+	}
+	ids := func(ids []int64) string {
+		s := make([]string, len(ids))
+		for i, id := range ids {
+			s[i] = strconv.FormatInt(id, 10)
+		}
+		return strings.Join(s, ", ")
+	}
+
+	field("ID", strconv.FormatInt(t.Id, 10))
+	field("Description", t.Description)
+	field("Status", workflow.Name(t.Status))
+	field("Priority", t.Priority.String())
+	if len(t.Tags) > 0 {
+		field("Tags", "+"+strings.Join(t.Tags, " +"))
+	}
+	field("Project", project)
+	if t.ParentID != 0 {
+		field("Parent", strconv.FormatInt(t.ParentID, 10))
+	}
+	field("Blocked by", ids(t.BlockedBy))
+	if !t.Due.IsZero() {
+		field("Due", formatDate(t.Due))
+	}
+	if t.Recurrence != nil {
+		field("Repeats", t.Recurrence.String())
+	}
+	field("Estimate", t.Estimate.String())
+	for _, def := range schema.Fields() {
+		value := def.Format(t.Fields[def.Name])
+		if def.Type == task.TypeDate && value != "" {
+			if d, err := time.Parse(time.RFC3339, value); err == nil {
+				value = formatDate(d)
+			}
+		}
+		field(def.Name, value)
+	}
+	if len(t.TimeLog) > 0 {
+		tracked := formatDuration(t.Tracked(now))
+		if t.Running() {
+			tracked += " (running)"
+		}
+		field("Tracked", tracked)
+	}
+	field("Created", t.CreatedAt.Local().Format(timeLayout))
+	if !t.UpdatedAt.IsZero() {
+		field("Updated", t.UpdatedAt.Local().Format(timeLayout))
+	}
+	if t.Trashed() {
+		field("Deleted", t.DeletedAt.Local().Format(timeLayout))
+	}
+	w.Flush()
+
+	if len(t.Notes) == 0 {
+		return b.String()
+	}
+	b.WriteString("\nNotes:\n")
+	for _, n := range t.Notes {
+		lines := strings.Split(n.Text, "\n")
+		fmt.Fprintf(&b, "  %s  %s\n", n.At.Local().Format(timeLayout), lines[0])
+		for _, line := range lines[1:] {
+			fmt.Fprintf(&b, "  %16s  %s\n", "", line)
+		}
+	}
+	return b.String()
+}
+
+// taskJSON is a task as written by show --json and list --format json, with
+// its status by name since custom status ids mean nothing to scripts
+type taskJSON struct {
+	task.Task
+	Status string `json:"status"`
+}
+
+func newTaskJSON(t task.Task, workflow task.Workflow) taskJSON {
+	return taskJSON{Task: t, Status: workflow.Name(t.Status)}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleTag(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli tag <id> [+tag] [-tag]")
+	}
+
+	if len(os.Args) < 4 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var add, remove []string
+	for _, arg := range os.Args[3:] {
+		if tag, ok := strings.CutPrefix(arg, "+"); ok {
+			add = append(add, tag)
+			continue
+		}
+		if tag, ok := strings.CutPrefix(arg, "-"); ok {
+			remove = append(remove, tag)
+			continue
+		}
+		showHelp()
+		return
+	}
+
+	if err := svc.Tag(id, add, remove); err != nil {
+		fmt.Println(fmt.Errorf("failed to tag task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Task tags updated successfully (ID: %d)\n", id)
+}
+
+func handleTags(svc task.Tasker) {
+	counts, err := svc.TagCounts()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to count tags: %w", err))
+		return
+	}
+	if len(counts) == 0 {
+		fmt.Println("No tags in use")
+		return
+	}
+
+	// Most used first, then alphabetical
+	tags := make([]string, 0, len(counts))
+	longestTag := 0
+	for tag := range counts {
+		tags = append(tags, tag)
+		longestTag = max(longestTag, len(tag))
+	}
+	slices.SortFunc(tags, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+
+	b := strings.Builder{}
+	for _, tag := range tags {
+		b.WriteRune('+')
+		b.WriteString(tag)
+		for i := 0; i < longestTag+2-len(tag); i++ {
+			b.WriteRune(' ')
+		}
+		b.WriteString(strconv.Itoa(counts[tag]))
+		b.WriteRune('\n')
+	}
+	fmt.Print(b.String())
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleStart(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli start <id> [--parallel] [--force]")
+	}
+
+	args := cli.ParseArgs(os.Args[2:], "parallel", "force")
+	if len(args.Positional) != 1 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, args.Positional[0])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var opts []task.MutationOption
+	if args.Bool("parallel") {
+		opts = append(opts, task.WithParallelTimers())
+	}
+	if args.Bool("force") {
+		opts = append(opts, task.WithForce())
+	}
+	if err := svc.Start(id, opts...); err != nil {
+		fmt.Println(fmt.Errorf("failed to start timer on task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Timer started (ID: %d)\n", id)
+}
+
+func handleStop(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli stop <id>")
+	}
+
+	if len(os.Args) != 3 {
+		showHelp()
+		return
+	}
+
+	id, err := resolveID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := svc.Stop(id); err != nil {
+		fmt.Println(fmt.Errorf("failed to stop timer on task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Timer stopped (ID: %d)\n", id)
+}
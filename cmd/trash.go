@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleTrash(svc task.Tasker) {
+	trashed, err := svc.Trash()
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to list trash: %w", err))
+		return
+	}
+	if len(trashed) == 0 {
+		fmt.Println("Trash is empty")
+		return
+	}
+
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, t := range trashed {
+		fmt.Fprintf(w, "%d\t%s\tdeleted %s\n", t.Id, t.Description, t.DeletedAt.Local().Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+	fmt.Print(b.String())
+}
+
+func handleRestore(svc task.Tasker) {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: task-cli restore <id>")
+		return
+	}
+
+	id, err := resolveTrashedID(svc, os.Args[2])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if err := svc.Restore(id); err != nil {
+		fmt.Println(fmt.Errorf("failed to restore task %d: %w", id, err))
+		return
+	}
+	fmt.Printf("Task restored successfully (ID: %d)\n", id)
+}
+
+func handlePurge(svc task.Tasker) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli purge [--older-than <duration, e.g. 30d>]")
+	}
+
+	args := cli.ParseArgs(os.Args[2:])
+	if len(args.Positional) > 0 {
+		showHelp()
+		return
+	}
+
+	var age time.Duration
+	if v, ok := args.Flag("older-than"); ok {
+		d, err := cli.ParseDuration(v)
+		if err != nil {
+			showHelp()
+			return
+		}
+		age = d
+	}
+
+	purged, err := svc.Purge(age)
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to purge trash: %w", err))
+		return
+	}
+	fmt.Printf("Purged %d task(s) from the trash\n", purged)
+}
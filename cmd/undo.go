@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleUndo(svc task.Tasker) {
+	events, err := svc.Undo()
+	if errors.Is(err, task.ErrNothingToUndo) {
+		fmt.Println("Nothing to undo")
+		return
+	}
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to undo: %w", err))
+		return
+	}
+	fmt.Printf("Undid %s\n", describeEvents(events))
+}
+
+func handleRedo(svc task.Tasker) {
+	events, err := svc.Redo()
+	if errors.Is(err, task.ErrNothingToRedo) {
+		fmt.Println("Nothing to redo")
+		return
+	}
+	if err != nil {
+		fmt.Println(fmt.Errorf("failed to redo: %w", err))
+		return
+	}
+	fmt.Printf("Redid %s\n", describeEvents(events))
+}
+
+// describeEvents summarises a transaction, e.g. "delete (ID: 3)"
+func describeEvents(events []task.Event) string {
+	if len(events) == 0 {
+		return "nothing"
+	}
+	ids := ""
+	for i, e := range events {
+		if i > 0 {
+			ids += ", "
+		}
+		ids += fmt.Sprint(e.TaskID)
+	}
+	return fmt.Sprintf("%s (ID: %s)", events[0].Type, ids)
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/ColinEge/task-cli/internal/task"
 )
@@ -14,13 +13,13 @@ func handleUpdate(svc task.Tasker) {
 		return
 	}
 
-	id, err := strconv.Atoi(os.Args[2])
+	id, err := resolveID(svc, os.Args[2])
 	if err != nil {
-		fmt.Println("Usage: task-cli update <id> <description>")
+		fmt.Println(err)
 		return
 	}
 
-	err = svc.Update(int64(id), task.Task{Description: os.Args[3]})
+	err = svc.Update(id, task.Task{Description: os.Args[3]})
 	if err != nil {
 		fmt.Println(fmt.Errorf("failed update task %d: %w", id, err))
 		return
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ColinEge/task-cli/internal/cli"
+	"github.com/ColinEge/task-cli/internal/config"
+	"github.com/ColinEge/task-cli/internal/query"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func handleView(svc task.Tasker, cfg config.Config) {
+	showHelp := func() {
+		fmt.Println("Usage: task-cli view <save <name> [list arguments...] [--format table|json|ids]|list|delete <name>|<name>>")
+	}
+
+	if len(os.Args) < 3 {
+		showHelp()
+		return
+	}
+
+	switch os.Args[2] {
+	case "save":
+		if len(os.Args) < 4 {
+			showHelp()
+			return
+		}
+		name := os.Args[3]
+		args, format, _ := cutFlag(os.Args[4:], "format")
+		if format != "" && !slices.Contains(listFormats, format) {
+			showHelp()
+			return
+		}
+		if isViewCommand(name) {
+			fmt.Println(fmt.Errorf("failed to save view: %w name %q, it is a view command", config.ErrInvalidView, name))
+			return
+		}
+		// Run the arguments the way list will, so a broken view isn't saved
+		opts, err := listOptions(svc, cli.ParseArgs(args))
+		if err == nil {
+			_, err = svc.List(opts)
+		}
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to save view: %w", err))
+			var qerr *query.Error
+			if errors.As(err, &qerr) {
+				fmt.Println(qerr.Caret())
+			}
+			return
+		}
+		err = config.Update(config.DefaultPath, func(cfg *config.Config) error {
+			return cfg.SetView(name, config.View{Args: args, Format: format})
+		})
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to save view: %w", err))
+			return
+		}
+		fmt.Printf("View saved successfully, run it with task-cli list @%s\n", strings.ToLower(name))
+	case "list":
+		if len(cfg.Views) == 0 {
+			fmt.Println("No saved views")
+			return
+		}
+		fmt.Print(formatViews(cfg.Views))
+	case "delete":
+		if len(os.Args) < 4 {
+			showHelp()
+			return
+		}
+		err := config.Update(config.DefaultPath, func(cfg *config.Config) error {
+			return cfg.DeleteView(os.Args[3])
+		})
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to delete view: %w", err))
+			return
+		}
+		fmt.Printf("View deleted successfully (%s)\n", strings.ToLower(os.Args[3]))
+	default:
+		v, err := cfg.View(strings.TrimPrefix(os.Args[2], "@"))
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to list tasks: %w", err))
+			return
+		}
+		listTasks(svc, viewArgs(v, os.Args[3:]))
+	}
+}
+
+func isViewCommand(name string) bool {
+	return slices.Contains([]string{"save", "list", "delete"}, strings.ToLower(name))
+}
+
+// viewArgs puts a saved view in front of any extra list arguments, so the
+// extra ones narrow the view down or override its flags
+func viewArgs(v config.View, extra []string) []string {
+	var args []string
+	if v.Format != "" {
+		args = append(args, "--format", v.Format)
+	}
+	args = append(args, v.Args...)
+	return append(args, extra...)
+}
+
+// cutFlag takes a --name value or --name=value flag out of args, leaving the
+// rest as they were given
+func cutFlag(args []string, name string) ([]string, string, bool) {
+	rest := make([]string, 0, len(args))
+	value, found := "", false
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		if v, ok := strings.CutPrefix(args[i], "--"+name+"="); ok {
+			value, found = v, true
+			continue
+		}
+		if args[i] == "--"+name && i+1 < len(args) {
+			value, found = args[i+1], true
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return rest, value, found
+}
+
+func formatViews(views map[string]config.View) string {
+	names := make([]string, 0, len(views))
+	for name := range views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := strings.Builder{}
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		v := views[name]
+		args := make([]string, len(v.Args))
+		for i, a := range v.Args {
+			args[i] = a
+			if a == "" || strings.ContainsAny(a, " \"'") {
+				args[i] = strconv.Quote(a)
+			}
+		}
+		format := v.Format
+		if format == "" {
+			format = "table"
+		}
+		fmt.Fprintf(w, "@%s\t%s\t%s\n", name, strings.Join(args, " "), format)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Args holds command line arguments split into positional values and --flags
+type Args struct {
+	Positional []string
+	Flags      map[string]string
+}
+
+// ParseArgs understands "--name value" and "--name=value". Flags named in
+// boolFlags never consume the following argument. Anything after a bare "--"
+// is positional
+func ParseArgs(args []string, boolFlags ...string) Args {
+	parsed := Args{Flags: map[string]string{}}
+	isBool := map[string]bool{}
+	for _, f := range boolFlags {
+		isBool[f] = true
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			parsed.Positional = append(parsed.Positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			parsed.Positional = append(parsed.Positional, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--")
+		if before, after, ok := strings.Cut(name, "="); ok {
+			parsed.Flags[before] = after
+			continue
+		}
+		if isBool[name] || i+1 >= len(args) {
+			parsed.Flags[name] = "true"
+			continue
+		}
+		parsed.Flags[name] = args[i+1]
+		i++
+	}
+	return parsed
+}
+
+func (a Args) Flag(name string) (string, bool) {
+	v, ok := a.Flags[name]
+	return v, ok
+}
+
+// Bool reports whether a flag is set to true, a value strconv.ParseBool
+// doesn't understand counts as false
+func (a Args) Bool(name string) bool {
+	v, ok := a.Flags[name]
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	return err == nil && b
+}
+
+// ParseDuration extends time.ParseDuration with whole days (d) and weeks (w),
+// e.g. "30d" or "2w"
+func ParseDuration(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if len(s) > 1 {
+		if unit, ok := units[s[len(s)-1]]; ok {
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return time.ParseDuration(s)
+}
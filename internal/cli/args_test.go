@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name               string
+		args               []string
+		boolFlags          []string
+		expectedPositional []string
+		expectedFlags      map[string]string
+	}{
+		{
+			name:               "Should split positional values and flags",
+			args:               []string{"purge", "--older-than", "30d", "extra"},
+			expectedPositional: []string{"purge", "extra"},
+			expectedFlags:      map[string]string{"older-than": "30d"},
+		},
+		{
+			name:               "Should read inline values and bool flags",
+			args:               []string{"--older-than=7d", "--cascade", "3"},
+			boolFlags:          []string{"cascade"},
+			expectedPositional: []string{"3"},
+			expectedFlags:      map[string]string{"older-than": "7d", "cascade": "true"},
+		},
+		{
+			name:               "Should keep single dash and values after -- positional",
+			args:               []string{"-blocked", "--", "--not-a-flag"},
+			expectedPositional: []string{"-blocked", "--not-a-flag"},
+			expectedFlags:      map[string]string{},
+		},
+	}
+
+	for _, tst := range tests {
+		actual := ParseArgs(tst.args, tst.boolFlags...)
+		if len(actual.Positional) != len(tst.expectedPositional) {
+			t.Errorf("%s expected positional %v but got %v", tst.name, tst.expectedPositional, actual.Positional)
+			continue
+		}
+		for i := range actual.Positional {
+			if actual.Positional[i] != tst.expectedPositional[i] {
+				t.Errorf("%s expected positional %v but got %v", tst.name, tst.expectedPositional, actual.Positional)
+			}
+		}
+		if len(actual.Flags) != len(tst.expectedFlags) {
+			t.Errorf("%s expected flags %v but got %v", tst.name, tst.expectedFlags, actual.Flags)
+		}
+		for k, v := range tst.expectedFlags {
+			if actual.Flags[k] != v {
+				t.Errorf("%s expected flags %v but got %v", tst.name, tst.expectedFlags, actual.Flags)
+			}
+		}
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: []string{"--cascade"}, expected: true},
+		{args: []string{"--cascade=true"}, expected: true},
+		{args: []string{"--cascade=1"}, expected: true},
+		{args: []string{"--cascade=false"}, expected: false},
+		{args: []string{"--cascade=no"}, expected: false},
+		{args: []string{"3"}, expected: false},
+	}
+
+	for _, tst := range tests {
+		if actual := ParseArgs(tst.args, "cascade").Bool("cascade"); actual != tst.expected {
+			t.Errorf("%v expected %v but got %v", tst.args, tst.expected, actual)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		falsy    bool
+	}{
+		{input: "30d", expected: 30 * 24 * time.Hour},
+		{input: "2w", expected: 14 * 24 * time.Hour},
+		{input: "1h30m", expected: 90 * time.Minute},
+		{input: "xd", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := ParseDuration(tst.input)
+		if tst.falsy {
+			if err == nil {
+				t.Errorf("%s expected an error but got %v", tst.input, actual)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		if actual != tst.expected {
+			t.Errorf("%s expected %v but got %v", tst.input, tst.expected, actual)
+		}
+	}
+}
@@ -8,10 +8,86 @@ func Help() {
 	fmt.Println("Usage: task-cli <command> [arguments]")
 	fmt.Println(`
 Commands:
-  add <description>              Add a new task
+  add <description>              Add a new task, +words in the description become tags
+    [--priority <level>]         with a priority (low, medium, high, urgent)
+    [--due <date>]               with a due date
+    [--project <name>]           in a project
+    [--parent <id>]              as a subtask of another task
+    [--repeat <rule>]            repeating, see repeat
+    [--estimate <duration>]      with an estimate like 30m or 2h
   update <id> <description>      Update a task
-  delete <id>                    Delete a task
-  mark-in-progress <id>          Mark a task as in progress
-  mark-done <id>                 Mark a task as done
-  list [|todo|in-progress|done]  List tasks (all or by status)`)
+  delete <id> [--cascade]        Move a task to the trash, --cascade includes subtasks
+  mark-in-progress <id>          Mark a task as in progress, --force starts blocked tasks
+  mark-done <id> [--cascade]     Mark a task as done, --cascade includes subtasks
+  mark-<status> <id>             Mark a task with a custom status from the workflow
+  prioritize <id> <level>        Set the priority of a task
+  due <id> <date|none>           Set or clear the due date of a task
+  list [|todo|in-progress|done]  List tasks (all or by status), highest priority first
+  list [<status>]                List tasks with a custom status
+  list [overdue|today|upcoming]  List unfinished tasks by due date
+  list [blocked|ready]           List unfinished tasks waiting on others, or free to start
+    [+tag] [-tag]                only with or without the given tags
+    [--priority <level>]         only with the given priority
+    [--project <name|none>]      only in a project, or in none
+    [--group project]            grouped by project with completion counts
+    ['<query>']                  only tasks matching a query, see below
+    [--sort <key[:desc],...>]    ordered by id, description, status, priority, due,
+                                 created, updated, project, estimate or a custom field
+    [--limit <n>] [--offset <n>] a page at a time, --after <id> continues after a task
+    [--format table|json|ids]    output as a table, JSON or one id per line
+  list @<view> [arguments...]    List with a saved view, extra arguments narrow it down
+  view save <name> [arguments]   Save list arguments and --format as a named view
+  view list                      List saved views
+  view delete <name>             Delete a saved view
+  view <name>                    Same as list @<name>
+  search <terms...>              Search descriptions and notes, best match first, words
+    [--limit <n>] [--reindex]    match by prefix, --reindex rebuilds the search index
+  tag <id> [+tag] [-tag]         Add or remove tags on a task
+  tags                           List every tag with how many tasks use it
+  repeat <id> <rule|none>        Repeat a task when done: daily, weekly [mon,thu],
+                                 monthly [day] or every <n> days
+  set <id> <field=value>...      Set custom fields from the config, field= clears one
+  note <id> ["text"|-]           Add a note to a task, read from stdin without text
+  show <id> [--json]             Show every field of a task with its notes
+  estimate <id> <dur|none>       Set or clear how long a task is expected to take
+  start <id> [--parallel]        Start a timer on a task and mark it in progress, stops
+                                 any other running timer unless --parallel is given
+  stop <id>                      Stop the timer on a task
+  report time                    Show tracked time per task, per tag and per day
+  report estimates               Compare estimates with the time from first start to done
+  block <id> --on <other>        Mark a task as blocked until another is done
+  unblock <id> --on <other>      Remove a blocked by dependency
+  project add <name> [desc]      Add a project
+  project list                   List projects with completion counts
+  project archive <name>         Archive a project so no new tasks can be added
+  project assign <id> <name>     Move a task and its subtasks to a project, none
+                                 takes them out of any project
+  trash                          List tasks in the trash
+  restore <id>                   Restore a task from the trash
+  purge [--older-than <age>]     Permanently remove trashed tasks
+  history <id>                   Show the change history of a task
+  undo                           Undo the last change
+  redo                           Redo the last undone change
+
+A task <id> can also be given as part of its description, "groc" finds
+"Buy groceries". When several tasks match you are asked to pick one, or the
+matches are listed when not run from a terminal.
+
+Dates can be written as YYYY-MM-DD [HH:MM] or as expressions like "tomorrow",
+"next friday", "in 3 days", "end of month" or "friday at 9am".
+
+Queries compare fields with :, =, !=, <, <=, >, >= or ~ (contains) and combine
+them with and, or, not and parentheses:
+  list 'status:todo and (tag:work or priority>=high) and created>-7d and desc~"deploy"'
+Fields are id, desc, status, priority, tag, project, parent, due, created,
+updated, estimate, is (a view such as overdue) and any custom field.
+
+Custom statuses, the moves allowed between them and custom fields (string,
+number, date or enum) are read from task-cli.json, saved views are kept there
+too:
+  {"version": 1, "workflow": {"statuses": ["review"],
+    "transitions": {"todo": ["in-progress"], "in-progress": ["review"],
+                    "review": ["done", "in-progress"]}},
+   "fields": [{"name": "points", "type": "number"},
+              {"name": "env", "type": "enum", "values": ["dev", "prod"]}]}`)
 }
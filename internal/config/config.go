@@ -0,0 +1,158 @@
+// Package config reads and writes the task-cli.json file that customises the
+// workflow, declares custom fields and keeps saved views
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// DefaultPath is where the CLI looks for its config, next to the task file
+const DefaultPath = "task-cli.json"
+
+// Version is the config format this build understands, files written for a
+// newer format are refused rather than half read
+const Version = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported config version")
+	ErrInvalidView        = errors.New("invalid view")
+	ErrViewNotFound       = errors.New("view not found")
+)
+
+type Config struct {
+	Version  int             `json:"version"`
+	Workflow Workflow        `json:"workflow,omitzero"`
+	Fields   []Field         `json:"fields,omitempty"`
+	Views    map[string]View `json:"views,omitempty"`
+}
+
+// Workflow lists custom statuses and the moves allowed out of each status,
+// leaving Transitions empty allows any move
+type Workflow struct {
+	Statuses    []string            `json:"statuses,omitempty"`
+	Transitions map[string][]string `json:"transitions,omitempty"`
+}
+
+// Field declares a custom field, Values lists the choices of an enum
+type Field struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Values []string `json:"values,omitempty"`
+}
+
+// View is a saved list command, Args are the list arguments (filters, --sort
+// and so on) and Format the output format, empty for the default table
+type View struct {
+	Args   []string `json:"args"`
+	Format string   `json:"format,omitempty"`
+}
+
+// Default is used when there is no config file
+func Default() Config {
+	return Config{Version: Version}
+}
+
+// Load reads the config at path, a missing file gives the defaults
+func Load(path string) (Config, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(bytes, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.Version < 1 || cfg.Version > Version {
+		return Config{}, fmt.Errorf("%w %d in %s, expected %d", ErrUnsupportedVersion, cfg.Version, path, Version)
+	}
+	return cfg, nil
+}
+
+// TaskWorkflow builds the workflow the task service enforces
+func (c Config) TaskWorkflow() (task.Workflow, error) {
+	return task.NewWorkflow(c.Workflow.Statuses, c.Workflow.Transitions)
+}
+
+// TaskSchema builds the custom fields the task service accepts
+func (c Config) TaskSchema() (task.Schema, error) {
+	defs := make([]task.FieldDef, len(c.Fields))
+	for i, f := range c.Fields {
+		defs[i] = task.FieldDef{Name: f.Name, Type: task.FieldType(f.Type), Values: f.Values}
+	}
+	return task.NewSchema(defs...)
+}
+
+// Save writes the config to path atomically, so a failed write never leaves a
+// half written config behind
+func Save(path string, cfg Config) error {
+	if cfg.Version == 0 {
+		cfg.Version = Version
+	}
+	bytes, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return task.WriteFileAtomic(path, append(bytes, '\n'))
+}
+
+// Update changes the config at path through fn and saves it, holding a lock
+// next to the file so two processes changing it at once can't lose either
+// change
+func Update(path string, fn func(cfg *Config) error) error {
+	unlock, err := task.LockFile(path+".lock", task.DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(&cfg); err != nil {
+		return err
+	}
+	return Save(path, cfg)
+}
+
+// View looks up a saved view by name, names are case insensitive
+func (c Config) View(name string) (View, error) {
+	v, ok := c.Views[strings.ToLower(name)]
+	if !ok {
+		return View{}, fmt.Errorf("%w with name %s", ErrViewNotFound, name)
+	}
+	return v, nil
+}
+
+// SetView adds or replaces a saved view
+func (c *Config) SetView(name string, v View) error {
+	name = strings.ToLower(name)
+	if name == "" || strings.ContainsAny(name, " \t@") || strings.IndexAny(name, "+-") == 0 {
+		return fmt.Errorf("%w name %q, names are single words without @ that don't start with + or -", ErrInvalidView, name)
+	}
+	if c.Views == nil {
+		c.Views = map[string]View{}
+	}
+	c.Views[name] = v
+	return nil
+}
+
+// DeleteView removes a saved view
+func (c *Config) DeleteView(name string) error {
+	name = strings.ToLower(name)
+	if _, ok := c.Views[name]; !ok {
+		return fmt.Errorf("%w with name %s", ErrViewNotFound, name)
+	}
+	delete(c.Views, name)
+	return nil
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError error
+		expected      []string
+	}{
+		{
+			name:     "missingFile",
+			expected: []string{"todo", "in-progress", "done"},
+		},
+		{
+			name: "customStatuses",
+			content: `{"version":1,"workflow":{"statuses":["todo","in-progress","review","done"],
+"transitions":{"in-progress":["review"],"review":["done"]}},
+"fields":[{"name":"env","type":"enum","values":["dev","prod"]}]}`,
+			expected: []string{"todo", "in-progress", "review", "done"},
+		},
+		{
+			name:          "newerVersion",
+			content:       `{"version":2}`,
+			expectedError: ErrUnsupportedVersion,
+		},
+		{
+			name:          "noVersion",
+			content:       `{"workflow":{}}`,
+			expectedError: ErrUnsupportedVersion,
+		},
+		{
+			name:          "badField",
+			content:       `{"version":1,"fields":[{"name":"size","type":"bool"}]}`,
+			expectedError: task.ErrInvalidSchema,
+		},
+		{
+			name:          "badWorkflow",
+			content:       `{"version":1,"workflow":{"transitions":{"todo":["shipped"]}}}`,
+			expectedError: task.ErrInvalidWorkflow,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultPath)
+			if tst.content != "" {
+				if err := os.WriteFile(path, []byte(tst.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(path)
+			var w task.Workflow
+			if err == nil {
+				w, err = cfg.TaskWorkflow()
+			}
+			if err == nil {
+				_, err = cfg.TaskSchema()
+			}
+			if tst.expectedError != nil {
+				if !errors.Is(err, tst.expectedError) {
+					t.Errorf("expected %v but got %v", tst.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			statuses := w.Statuses()
+			if len(statuses) != len(tst.expected) {
+				t.Fatalf("expected %v but got %d statuses", tst.expected, len(statuses))
+			}
+			for i, s := range statuses {
+				if w.Name(s) != tst.expected[i] {
+					t.Errorf("expected %s at %d but got %s", tst.expected[i], i, w.Name(s))
+				}
+			}
+		})
+	}
+}
+
+func TestViews(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	if err := os.WriteFile(path, []byte(`{"version":1,"fields":[{"name":"points","type":"number"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"", "my view", "@mine", "-mine"} {
+		if err := cfg.SetView(name, View{}); !errors.Is(err, ErrInvalidView) {
+			t.Errorf("%q expected %v but got %v", name, ErrInvalidView, err)
+		}
+	}
+	if err := cfg.SetView("Mine", View{Args: []string{"tag:work", "--sort", "-created"}, Format: "json"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.SetView("stale", View{Args: []string{"overdue"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DeleteView("stale"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.DeleteView("stale"); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("expected %v but got %v", ErrViewNotFound, err)
+	}
+	if err := Save(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saving keeps the rest of the config and leaves no temp files behind
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Fields) != 1 || len(loaded.Views) != 1 {
+		t.Errorf("expected the field and one view but got %+v", loaded)
+	}
+	v, err := loaded.View("MINE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(v.Args, " ") != "tag:work --sort -created" || v.Format != "json" {
+		t.Errorf("expected the saved view but got %+v", v)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected only the config file but got %v", entries)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("expected the config to stay readable but got %v", info.Mode())
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultPath)
+
+	// Concurrent updates each see the others' changes
+	errs := make(chan error)
+	for i := range 10 {
+		go func() {
+			errs <- Update(path, func(cfg *Config) error {
+				return cfg.SetView(fmt.Sprintf("view%d", i), View{Args: []string{"overdue"}})
+			})
+		}()
+	}
+	for range 10 {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Views) != 10 {
+		t.Errorf("expected 10 views but got %v", cfg.Views)
+	}
+
+	// A failing change leaves the file alone
+	if err := Update(path, func(cfg *Config) error { return cfg.DeleteView("missing") }); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("expected %v but got %v", ErrViewNotFound, err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package dateparse turns the dates people type at a shell into times.
+//
+// Everything is resolved relative to a supplied now so results are
+// deterministic. Expressions without a time of day resolve to midnight in
+// now's location. Supported forms include:
+//
+//	now, today, tomorrow, yesterday
+//	friday, next friday, this friday
+//	next week, next month, next year
+//	end of week, end of month, end of year
+//	in 3 days, in 2 weeks, in 4 hours, 3 days ago
+//	+3d, -7d, 2w, 12h
+//	2026-11-02, 2026-11-02 14:00, RFC 3339 timestamps
+//
+// Relative dates may end with a time such as "tomorrow 14:00" or
+// "friday at 9am".
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnrecognized = errors.New("unrecognized date")
+
+var (
+	timeSuffix = regexp.MustCompile(`^(.*?)(?:\s+at)?\s+(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	relative   = regexp.MustCompile(`^in\s+(\d+)\s+(\w+)$`)
+	ago        = regexp.MustCompile(`^(\d+)\s+(\w+)\s+ago$`)
+	short      = regexp.MustCompile(`^([+-]?)(\d+)([hdw])$`)
+)
+
+var absoluteLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// Parse resolves s relative to now
+func Parse(s string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("%w: empty date", ErrUnrecognized)
+	}
+
+	if t, ok := parseAbsolute(input, now.Location()); ok {
+		return t, nil
+	}
+	if t, ok := parseRelative(input, now); ok {
+		return t, nil
+	}
+
+	// Try again with a trailing time of day split off
+	if m := timeSuffix.FindStringSubmatch(input); m != nil && (m[3] != "" || m[4] != "") {
+		day, ok := parseRelative(m[1], now)
+		if ok {
+			if t, ok := withClock(day, m[2], m[3], m[4]); ok {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w %q", ErrUnrecognized, s)
+}
+
+func parseAbsolute(input string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, input, loc); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(input)); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func parseRelative(input string, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+
+	switch input {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "next week":
+		return today.AddDate(0, 0, 7), true
+	case "next month":
+		return addMonths(today, 1), true
+	case "next year":
+		return addMonths(today, 12), true
+	case "end of week":
+		// Weeks end on Sunday
+		return today.AddDate(0, 0, (7-int(today.Weekday()))%7), true
+	case "end of month":
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return first.AddDate(0, 1, -1), true
+	case "end of year":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), true
+	}
+
+	if day, ok := ParseWeekday(strings.TrimPrefix(input, "next ")); ok {
+		return nextWeekday(today, day, false), true
+	}
+	if name, found := strings.CutPrefix(input, "this "); found {
+		if day, ok := ParseWeekday(name); ok {
+			return nextWeekday(today, day, true), true
+		}
+	}
+
+	if m := relative.FindStringSubmatch(input); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return shift(now, today, n, m[2])
+	}
+	if m := ago.FindStringSubmatch(input); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return shift(now, today, -n, m[2])
+	}
+	if m := short.FindStringSubmatch(input); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		return shift(now, today, n, m[3])
+	}
+	return time.Time{}, false
+}
+
+// shift moves n units away. Units of a day or longer land on midnight, shorter
+// units keep the time of day
+func shift(now, today time.Time, n int, unit string) (time.Time, bool) {
+	switch strings.TrimSuffix(unit, "s") {
+	case "minute", "min":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "hour", "h":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "day", "d":
+		return today.AddDate(0, 0, n), true
+	case "week", "w":
+		return today.AddDate(0, 0, 7*n), true
+	case "month":
+		return addMonths(today, n), true
+	case "year":
+		return addMonths(today, 12*n), true
+	}
+	return time.Time{}, false
+}
+
+// ParseWeekday reads a full or three letter weekday name such as "friday" or
+// "fri"
+func ParseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// nextWeekday finds the next given weekday after today, or today itself when
+// includeToday is set
+func nextWeekday(today time.Time, day time.Weekday, includeToday bool) time.Time {
+	days := (int(day) - int(today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// addMonths clamps to the end of shorter months so Jan 31 + 1 month is Feb 28
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := min(t.Day(), lastDay)
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func withClock(day time.Time, hour, minute, meridiem string) (time.Time, bool) {
+	h, err := strconv.Atoi(hour)
+	if err != nil {
+		return time.Time{}, false
+	}
+	m := 0
+	if minute != "" {
+		if m, err = strconv.Atoi(minute); err != nil || m > 59 {
+			return time.Time{}, false
+		}
+	}
+	switch meridiem {
+	case "am", "pm":
+		if h < 1 || h > 12 {
+			return time.Time{}, false
+		}
+		h %= 12
+		if meridiem == "pm" {
+			h += 12
+		}
+	default:
+		if h > 23 {
+			return time.Time{}, false
+		}
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location()), true
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,80 @@
+package dateparse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// A Wednesday afternoon
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		falsy    bool
+	}{
+		{input: "now", expected: now},
+		{input: "today", expected: date(2026, time.October, 14, 0, 0)},
+		{input: "Tomorrow", expected: date(2026, time.October, 15, 0, 0)},
+		{input: "yesterday", expected: date(2026, time.October, 13, 0, 0)},
+		{input: "friday", expected: date(2026, time.October, 16, 0, 0)},
+		{input: "next friday", expected: date(2026, time.October, 16, 0, 0)},
+		{input: "wednesday", expected: date(2026, time.October, 21, 0, 0)},
+		{input: "this wed", expected: date(2026, time.October, 14, 0, 0)},
+		{input: "in 3 days", expected: date(2026, time.October, 17, 0, 0)},
+		{input: "in 2 weeks", expected: date(2026, time.October, 28, 0, 0)},
+		{input: "in 4 hours", expected: date(2026, time.October, 14, 19, 30)},
+		{input: "3 days ago", expected: date(2026, time.October, 11, 0, 0)},
+		{input: "-7d", expected: date(2026, time.October, 7, 0, 0)},
+		{input: "+1w", expected: date(2026, time.October, 21, 0, 0)},
+		{input: "next week", expected: date(2026, time.October, 21, 0, 0)},
+		{input: "next month", expected: date(2026, time.November, 14, 0, 0)},
+		{input: "end of week", expected: date(2026, time.October, 18, 0, 0)},
+		{input: "end of month", expected: date(2026, time.October, 31, 0, 0)},
+		{input: "end of year", expected: date(2026, time.December, 31, 0, 0)},
+		{input: "2026-11-02", expected: date(2026, time.November, 2, 0, 0)},
+		{input: "2026-11-02 14:00", expected: date(2026, time.November, 2, 14, 0)},
+		{input: "2026-11-02T14:00:00Z", expected: date(2026, time.November, 2, 14, 0)},
+		{input: "tomorrow 14:00", expected: date(2026, time.October, 15, 14, 0)},
+		{input: "friday at 9am", expected: date(2026, time.October, 16, 9, 0)},
+		{input: "end of month at 5:30pm", expected: date(2026, time.October, 31, 17, 30)},
+		{input: "someday", falsy: true},
+		{input: "in 3 fortnights", falsy: true},
+		{input: "tomorrow 25:00", falsy: true},
+		{input: "", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := Parse(tst.input, now)
+		if tst.falsy {
+			if !errors.Is(err, ErrUnrecognized) {
+				t.Errorf("%q expected %v but got %v, %v", tst.input, ErrUnrecognized, actual, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tst.input, err)
+			continue
+		}
+		if !actual.Equal(tst.expected) {
+			t.Errorf("%q expected %v but got %v", tst.input, tst.expected, actual)
+		}
+	}
+}
+
+func TestParseMonthEnds(t *testing.T) {
+	now := time.Date(2026, time.January, 31, 12, 0, 0, 0, time.UTC)
+	actual, err := Parse("next month", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC)
+	if !actual.Equal(expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
@@ -0,0 +1,128 @@
+// Package fuzzy matches short typed patterns against task descriptions, so a
+// task can be picked by a few letters of its description instead of its id
+package fuzzy
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Match is an item that matched a pattern, Index points into the searched items
+type Match struct {
+	Index int
+	Score int
+}
+
+const (
+	scoreChar        = 1
+	scoreConsecutive = 5
+	scoreWordStart   = 8
+	scoreSubstring   = 20
+	maxGapPenalty    = 10
+)
+
+// Score reports whether every letter of the pattern appears in text in order,
+// ignoring case and spaces in the pattern. Letters matched in a row, at the
+// start of words or as one substring score higher
+func Score(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(strings.Join(strings.Fields(pattern), "")))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, false
+	}
+
+	best, found := 0, false
+	for start := range t {
+		if t[start] != p[0] {
+			continue
+		}
+		score, ok := scoreFrom(p, t, start)
+		if ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	if !found {
+		return 0, false
+	}
+	if strings.Contains(strings.ToLower(text), strings.ToLower(strings.TrimSpace(pattern))) {
+		best += scoreSubstring
+	}
+	return best, true
+}
+
+// scoreFrom greedily matches p against t starting at t[start]
+func scoreFrom(p, t []rune, start int) (int, bool) {
+	score, prev := 0, -1
+	j := start
+	for _, r := range p {
+		for j < len(t) && t[j] != r {
+			j++
+		}
+		if j == len(t) {
+			return 0, false
+		}
+		score += scoreChar
+		if prev >= 0 && j == prev+1 {
+			score += scoreConsecutive
+		}
+		if j == 0 || !isWordRune(t[j-1]) {
+			score += scoreWordStart
+		}
+		if prev >= 0 {
+			score -= min(j-prev-1, maxGapPenalty)
+		}
+		prev = j
+		j++
+	}
+	return score, true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Rank returns the items matching pattern, best first. Ties keep item order
+func Rank(pattern string, items []string) []Match {
+	var matches []Match
+	for i, item := range items {
+		if score, ok := Score(pattern, item); ok {
+			matches = append(matches, Match{Index: i, Score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return matches
+}
+
+// Candidates narrows the ranked matches down to the ones a user most likely
+// meant. An item equal to the pattern wins outright, then items containing it
+// as written, and only when there are neither are looser matches offered
+func Candidates(pattern string, items []string) []Match {
+	matches := Rank(pattern, items)
+	needle := strings.ToLower(strings.TrimSpace(pattern))
+
+	var exact, substring []Match
+	for _, m := range matches {
+		if strings.ToLower(strings.TrimSpace(items[m.Index])) == needle {
+			exact = append(exact, m)
+		}
+		if Contains(pattern, items[m.Index]) {
+			substring = append(substring, m)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	if len(substring) > 0 {
+		return substring
+	}
+	return matches
+}
+
+// Contains reports whether text contains pattern as written, ignoring case
+func Contains(pattern, text string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(strings.TrimSpace(pattern)))
+}
@@ -0,0 +1,90 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		falsy   bool
+	}{
+		{pattern: "groc", text: "Buy groceries"},
+		{pattern: "BUY GROC", text: "Buy groceries"},
+		{pattern: "bg", text: "Buy groceries"},
+		{pattern: "gb", text: "Buy groceries", falsy: true},
+		{pattern: "milk", text: "Buy groceries", falsy: true},
+		{pattern: "", text: "Buy groceries", falsy: true},
+	}
+
+	for _, tst := range tests {
+		_, ok := Score(tst.pattern, tst.text)
+		if ok == tst.falsy {
+			t.Errorf("%q in %q expected match %v but got %v", tst.pattern, tst.text, !tst.falsy, ok)
+		}
+	}
+
+	// Word starts and letters in a row beat the same letters scattered
+	tight, _ := Score("fix", "Fix login page")
+	loose, _ := Score("fix", "Find six boxes")
+	if tight <= loose {
+		t.Errorf("expected %d to score higher than %d", tight, loose)
+	}
+}
+
+func TestRank(t *testing.T) {
+	items := []string{"Pay rent", "Deploy the API", "Write deployment notes", "Buy milk"}
+	expected := []int{1, 2}
+	actual := []int{}
+	for _, m := range Rank("depl", items) {
+		actual = append(actual, m.Index)
+	}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	items := []string{"Buy groceries", "Groceries budget", "Book venue", "buy groceries "}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		expected []int
+	}{
+		{name: "Should prefer exact descriptions", pattern: "buy groceries", expected: []int{0, 3}},
+		{name: "Should prefer substrings over loose matches", pattern: "groceries", expected: []int{0, 1, 3}},
+		{name: "Should fall back to loose matches", pattern: "bkvn", expected: []int{2}},
+		{name: "Should find nothing", pattern: "zzz", expected: []int{}},
+	}
+
+	for _, tst := range tests {
+		actual := []int{}
+		for _, m := range Candidates(tst.pattern, items) {
+			actual = append(actual, m.Index)
+		}
+		if !slices.Equal(actual, tst.expected) {
+			t.Errorf("%s expected %v but got %v", tst.name, tst.expected, actual)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		text     string
+		expected bool
+	}{
+		{pattern: "GROC", text: "Buy groceries", expected: true},
+		{pattern: " venue ", text: "Book venue", expected: true},
+		{pattern: "bkvn", text: "Book venue", expected: false},
+	}
+
+	for _, tst := range tests {
+		if actual := Contains(tst.pattern, tst.text); actual != tst.expected {
+			t.Errorf("%q in %q expected %v but got %v", tst.pattern, tst.text, tst.expected, actual)
+		}
+	}
+}
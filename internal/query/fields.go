@@ -0,0 +1,253 @@
+package query
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/dateparse"
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+var (
+	equalityOps = []string{":", "=", "!="}
+	orderingOps = []string{":", "=", "!=", "<", "<=", ">", ">="}
+	textOps     = []string{":", "=", "!=", "~"}
+)
+
+// comparison resolves a field, operator and value into a predicate
+func (p *parser) comparison(field, op, value token) (node, error) {
+	allow := func(ops []string) error {
+		if slices.Contains(ops, op.text) {
+			return nil
+		}
+		return p.errorf(op, "operator %q can't be used with %s, expected one of %s", op.text, field.text, strings.Join(ops, " "))
+	}
+	invalid := func(err error) error {
+		return p.errorf(value, "%v", err)
+	}
+
+	switch strings.ToLower(field.text) {
+	case "id", "parent":
+		if err := allow(orderingOps); err != nil {
+			return nil, err
+		}
+		get := func(t task.Task) int64 { return t.Id }
+		if strings.EqualFold(field.text, "parent") {
+			get = func(t task.Task) int64 { return t.ParentID }
+		}
+		want := int64(0)
+		if !strings.EqualFold(value.text, "none") {
+			n, err := strconv.ParseInt(value.text, 10, 64)
+			if err != nil || n < 0 {
+				return nil, p.errorf(value, "expected a task id or none but got %s", value.describe())
+			}
+			want = n
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			return compare(op.text, cmp.Compare(get(t), want))
+		}), nil
+
+	case "desc", "description":
+		if err := allow(textOps); err != nil {
+			return nil, err
+		}
+		want := strings.ToLower(value.text)
+		return predicate(func(t task.Task, _ task.Env) bool {
+			desc := strings.ToLower(t.Description)
+			switch op.text {
+			case ":", "~":
+				return strings.Contains(desc, want)
+			case "=":
+				return desc == want
+			}
+			return desc != want
+		}), nil
+
+	case "status":
+		if err := allow(equalityOps); err != nil {
+			return nil, err
+		}
+		status, err := p.ctx.Workflow.Parse(value.text)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			return compare(op.text, cmp.Compare(t.Status, status))
+		}), nil
+
+	case "priority":
+		if err := allow(orderingOps); err != nil {
+			return nil, err
+		}
+		priority, err := task.ParsePriority(value.text)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			return compare(op.text, cmp.Compare(t.Priority, priority))
+		}), nil
+
+	case "tag", "tags":
+		if err := allow(textOps); err != nil {
+			return nil, err
+		}
+		want := strings.ToLower(strings.TrimPrefix(value.text, "+"))
+		return predicate(func(t task.Task, _ task.Env) bool {
+			if op.text == "~" {
+				return slices.ContainsFunc(t.Tags, func(tag string) bool { return strings.Contains(tag, want) })
+			}
+			return t.HasTag(want) == (op.text != "!=")
+		}), nil
+
+	case "project":
+		if err := allow(equalityOps); err != nil {
+			return nil, err
+		}
+		want := int64(0)
+		if !strings.EqualFold(value.text, "none") {
+			i := slices.IndexFunc(p.ctx.Projects, func(pr task.Project) bool { return strings.EqualFold(pr.Name, value.text) })
+			if i < 0 {
+				return nil, p.errorf(value, "%v %q", task.ErrProjectNotFound, value.text)
+			}
+			want = p.ctx.Projects[i].Id
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			return compare(op.text, cmp.Compare(t.ProjectID, want))
+		}), nil
+
+	case "due", "created", "updated":
+		if err := allow(orderingOps); err != nil {
+			return nil, err
+		}
+		get := map[string]func(task.Task) time.Time{
+			"due":     func(t task.Task) time.Time { return t.Due },
+			"created": func(t task.Task) time.Time { return t.CreatedAt },
+			"updated": func(t task.Task) time.Time { return t.UpdatedAt },
+		}[strings.ToLower(field.text)]
+		if strings.EqualFold(value.text, "none") {
+			if err := allow(equalityOps); err != nil {
+				return nil, err
+			}
+			return predicate(func(t task.Task, _ task.Env) bool {
+				return get(t).IsZero() == (op.text != "!=")
+			}), nil
+		}
+		want, err := dateparse.Parse(value.text, p.ctx.Now)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			have := get(t)
+			if have.IsZero() {
+				return op.text == "!="
+			}
+			// A date without a time of day covers the whole day, so due:today
+			// matches any time today and due<=friday takes in friday evening
+			if slices.Contains(equalityOps, op.text) || want.Equal(startOfDay(want)) {
+				return compare(op.text, sameDay(have, want))
+			}
+			return compare(op.text, have.Compare(want))
+		}), nil
+
+	case "estimate":
+		if err := allow(orderingOps); err != nil {
+			return nil, err
+		}
+		var want task.Estimate
+		if !strings.EqualFold(value.text, "none") {
+			e, err := task.ParseEstimate(value.text)
+			if err != nil {
+				return nil, invalid(err)
+			}
+			want = e
+		}
+		return predicate(func(t task.Task, _ task.Env) bool {
+			if t.Estimate == 0 && want != 0 {
+				return op.text == "!="
+			}
+			return compare(op.text, cmp.Compare(t.Estimate, want))
+		}), nil
+
+	case "is":
+		if err := allow(equalityOps); err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(task.Views(), func(v task.View) bool { return strings.EqualFold(string(v), value.text) })
+		if i < 0 {
+			names := make([]string, len(task.Views()))
+			for j, v := range task.Views() {
+				names[j] = string(v)
+			}
+			return nil, p.errorf(value, "unknown view %s, expected one of %s", value.describe(), strings.Join(names, ", "))
+		}
+		view := task.Views()[i]
+		return predicate(func(t task.Task, env task.Env) bool {
+			return view.Match(t, env) == (op.text != "!=")
+		}), nil
+	}
+
+	return p.customField(field, op, value)
+}
+
+// customField defers to the schema, ":" is an alias for "=" and "~" is a
+// substring match on string fields
+func (p *parser) customField(field, op, value token) (node, error) {
+	def, ok := p.ctx.Schema.Lookup(field.text)
+	if !ok {
+		return nil, p.errorf(field, "unknown field %q", field.text)
+	}
+	if op.text == "~" {
+		if def.Type != task.TypeString {
+			return nil, p.errorf(op, `operator "~" only works on text but %s is a %s`, def.Name, def.Type)
+		}
+		want := strings.ToLower(value.text)
+		return predicate(func(t task.Task, _ task.Env) bool {
+			return strings.Contains(strings.ToLower(def.Format(t.Fields[def.Name])), want)
+		}), nil
+	}
+
+	opText := op.text
+	if opText == ":" {
+		opText = "="
+	}
+	m, err := p.ctx.Schema.Filter(task.FieldFilter{Field: def.Name, Op: opText, Value: value.text}, p.ctx.Now)
+	if err != nil {
+		return nil, p.errorf(value, "%v", err)
+	}
+	return predicate(m.Match), nil
+}
+
+// sameDay compares the calendar days of two times in b's location
+func sameDay(a, b time.Time) int {
+	a = a.In(b.Location())
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return cmp.Or(cmp.Compare(ay, by), cmp.Compare(am, bm), cmp.Compare(ad, bd))
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// compare turns the result of a three way comparison into the answer for op
+func compare(op string, c int) bool {
+	switch op {
+	case ":", "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
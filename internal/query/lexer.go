@@ -0,0 +1,152 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenWord:
+		return "word"
+	case tokenString:
+		return "string"
+	case tokenOp:
+		return "operator"
+	case tokenLParen:
+		return `"("`
+	case tokenRParen:
+		return `")"`
+	case tokenAnd:
+		return `"and"`
+	case tokenOr:
+		return `"or"`
+	case tokenNot:
+		return `"not"`
+	}
+	return "token"
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the byte offset of the token in the query
+	pos int
+}
+
+// describe names a token for error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokenWord, tokenOp:
+		return `"` + t.text + `"`
+	case tokenString:
+		return "string " + `"` + t.text + `"`
+	}
+	return t.kind.String()
+}
+
+// operators are matched longest first
+var operators = []string{"!=", "<=", ">=", ":", "=", "<", ">", "~"}
+
+var keywords = map[string]tokenKind{"and": tokenAnd, "or": tokenOr, "not": tokenNot}
+
+// lex splits a query into tokens. Words run until white space, a paren, a
+// quote or an operator, so "-7d" and "in-progress" are single words
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r := rune(src[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case r == '"' || r == '\'':
+			text, end, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i = end
+		default:
+			if op := operatorAt(src, i); op != "" {
+				tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			if r == '!' {
+				return nil, &Error{Src: src, Pos: i, Msg: `unexpected "!", did you mean "!=" or "not"`}
+			}
+			start := i
+			for i < len(src) && !isWordEnd(src, i) {
+				i++
+			}
+			text := src[start:i]
+			kind := tokenWord
+			if k, ok := keywords[strings.ToLower(text)]; ok {
+				kind = k
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+func operatorAt(src string, i int) string {
+	for _, op := range operators {
+		if strings.HasPrefix(src[i:], op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isWordEnd(src string, i int) bool {
+	switch src[i] {
+	case ' ', '\t', '\n', '\r', '(', ')', '"', '\'', '!':
+		return true
+	}
+	return operatorAt(src, i) != ""
+}
+
+// lexString reads a quoted string starting at i, a backslash escapes the next
+// character
+func lexString(src string, i int) (string, int, error) {
+	quote := src[i]
+	b := strings.Builder{}
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			if j+1 < len(src) {
+				j++
+				b.WriteByte(src[j])
+			}
+		case quote:
+			return b.String(), j + 1, nil
+		default:
+			b.WriteByte(src[j])
+		}
+	}
+	return "", 0, &Error{Src: src, Pos: i, Msg: "unterminated string"}
+}
@@ -0,0 +1,82 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []token
+	}{
+		{
+			input: `status:in-progress and created>-7d`,
+			expected: []token{
+				{kind: tokenWord, text: "status", pos: 0},
+				{kind: tokenOp, text: ":", pos: 6},
+				{kind: tokenWord, text: "in-progress", pos: 7},
+				{kind: tokenAnd, text: "and", pos: 19},
+				{kind: tokenWord, text: "created", pos: 23},
+				{kind: tokenOp, text: ">", pos: 30},
+				{kind: tokenWord, text: "-7d", pos: 31},
+				{kind: tokenEOF, pos: 34},
+			},
+		},
+		{
+			input: `NOT (priority>=high OR desc~"say \"hi\"")`,
+			expected: []token{
+				{kind: tokenNot, text: "NOT", pos: 0},
+				{kind: tokenLParen, text: "(", pos: 4},
+				{kind: tokenWord, text: "priority", pos: 5},
+				{kind: tokenOp, text: ">=", pos: 13},
+				{kind: tokenWord, text: "high", pos: 15},
+				{kind: tokenOr, text: "OR", pos: 20},
+				{kind: tokenWord, text: "desc", pos: 23},
+				{kind: tokenOp, text: "~", pos: 27},
+				{kind: tokenString, text: `say "hi"`, pos: 28},
+				{kind: tokenRParen, text: ")", pos: 40},
+				{kind: tokenEOF, pos: 41},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		actual, err := lex(tst.input)
+		if err != nil {
+			t.Errorf("%q: %v", tst.input, err)
+			continue
+		}
+		if len(actual) != len(tst.expected) {
+			t.Errorf("%q expected %v but got %v", tst.input, tst.expected, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != tst.expected[i] {
+				t.Errorf("%q expected token %d to be %+v but got %+v", tst.input, i, tst.expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedPos int
+	}{
+		{input: `desc~"unterminated`, expectedPos: 5},
+		{input: `status:todo and !tag:work`, expectedPos: 16},
+	}
+
+	for _, tst := range tests {
+		_, err := lex(tst.input)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Errorf("%q expected a query error but got %v", tst.input, err)
+			continue
+		}
+		if qerr.Pos != tst.expectedPos {
+			t.Errorf("%q expected the error at %d but got %d", tst.input, tst.expectedPos, qerr.Pos)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+type node interface {
+	match(t task.Task, env task.Env) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(t task.Task, env task.Env) bool {
+	return n.left.match(t, env) && n.right.match(t, env)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(t task.Task, env task.Env) bool {
+	return n.left.match(t, env) || n.right.match(t, env)
+}
+
+type notNode struct{ inner node }
+
+func (n notNode) match(t task.Task, env task.Env) bool {
+	return !n.inner.match(t, env)
+}
+
+// predicate is a single comparison with its field and value already resolved
+type predicate func(t task.Task, env task.Env) bool
+
+func (p predicate) match(t task.Task, env task.Env) bool {
+	return p(t, env)
+}
+
+// parser is a recursive descent parser over
+//
+//	or         = and { "or" and }
+//	and        = unary { ["and"] unary }
+//	unary      = "not" unary | primary
+//	primary    = "(" or ")" | comparison
+//	comparison = word operator (word | string)
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+	ctx    Context
+}
+
+func (p *parser) parse() (node, error) {
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok.describe())
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokenEOF {
+		p.i++
+	}
+	return tok
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) *Error {
+	return &Error{Src: p.src, Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenNot, tokenLParen:
+			// comparisons side by side are joined with and
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorf(closing, `expected ")" to close the "(" at column %d but got %s`, tok.pos+1, closing.describe())
+		}
+		return n, nil
+	case tokenWord:
+		op := p.next()
+		if op.kind != tokenOp {
+			return nil, p.errorf(op, "expected an operator after %q but got %s", tok.text, op.describe())
+		}
+		value := p.next()
+		if value.kind != tokenWord && value.kind != tokenString {
+			return nil, p.errorf(value, "expected a value after %q but got %s", tok.text+op.text, value.describe())
+		}
+		return p.comparison(tok, op, value)
+	}
+	return nil, p.errorf(tok, "expected a comparison such as status:todo but got %s", tok.describe())
+}
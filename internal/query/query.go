@@ -0,0 +1,84 @@
+// Package query compiles filter expressions for the task list such as
+//
+//	status:todo and (tag:work or priority>=high) and created>-7d and desc~"deploy"
+//
+// A query is comparisons joined with and, or and not, grouped with
+// parentheses. Comparisons next to each other without a keyword are joined
+// with and. Operators are ":" and "=" for equality (":" is a substring match
+// on text), "!=", "<", "<=", ">", ">=" and "~" for a substring match. Values
+// are single words or quoted strings.
+//
+// Fields are id, desc (or description), status, priority, tag, project,
+// parent, due, created, updated, estimate, is (one of the list views such as
+// overdue or blocked) and any custom field from the schema. Dates accept
+// anything the dateparse package does, such as "today" or "-7d", and "none"
+// matches tasks without a due date, project, parent or estimate.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+// Error points at the token a query failed on
+type Error struct {
+	Src string
+	// Pos is the byte offset of the offending token
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Caret shows the query with a marker under the offending token, e.g.
+//
+//	status:todo and priority>=hihg
+//	                          ^
+func (e *Error) Caret() string {
+	return e.Src + "\n" + strings.Repeat(" ", e.Pos) + "^"
+}
+
+// Context is what a query is checked against when it is compiled, names of
+// statuses, priorities, projects and custom fields are resolved up front so
+// mistakes are reported with their position
+type Context struct {
+	Now      time.Time
+	Workflow task.Workflow
+	Schema   task.Schema
+	Projects []task.Project
+}
+
+// Query is a compiled query, ready to pass to task.ListOptions.Filter
+type Query struct {
+	src  string
+	root node
+}
+
+var _ task.Matcher = (*Query)(nil)
+
+// Compile parses a query and resolves every field and value in it
+func Compile(src string, ctx Context) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{src: src, tokens: tokens, ctx: ctx}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+func (q *Query) Match(t task.Task, env task.Env) bool {
+	return q.root.match(t, env)
+}
+
+func (q *Query) String() string {
+	return q.src
+}
@@ -0,0 +1,138 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/task"
+)
+
+func TestCompile(t *testing.T) {
+	now, _ := time.Parse(time.RFC3339, "2026-01-14T12:00:00Z")
+	review, err := task.NewWorkflow([]string{"review"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inReview, _ := review.Parse("review")
+	schema, err := task.NewSchema(
+		task.FieldDef{Name: "customer", Type: task.TypeString},
+		task.FieldDef{Name: "points", Type: task.TypeNumber},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := Context{
+		Now:      now,
+		Workflow: review,
+		Schema:   schema,
+		Projects: []task.Project{{Id: 1, Name: "website"}},
+	}
+
+	tasks := []task.Task{
+		{Id: 1, Description: "Deploy the API", Status: task.StatusTodo, Priority: task.PriorityHigh, Tags: []string{"work"}, CreatedAt: now.AddDate(0, 0, -2), Due: now.AddDate(0, 0, -1)},
+		{Id: 2, Description: "Write deploy notes", Status: task.StatusInProgress, Tags: []string{"docs", "work"}, ProjectID: 1, CreatedAt: now.AddDate(0, 0, -10), Fields: map[string]any{"customer": "Acme", "points": 3.0}},
+		{Id: 3, Description: "Buy milk", Status: task.StatusTodo, Priority: task.PriorityLow, CreatedAt: now, Due: now, Estimate: task.Estimate(30 * time.Minute)},
+		{Id: 4, Description: "Review deploy script", Status: inReview, Priority: task.PriorityUrgent, ParentID: 1, CreatedAt: now.AddDate(0, 0, -1), BlockedBy: []int64{3}, Fields: map[string]any{"points": 8.0}},
+	}
+	env := task.Env{Now: now, Tasks: map[int64]task.Task{}}
+	for _, t := range tasks {
+		env.Tasks[t.Id] = t
+	}
+
+	tests := []struct {
+		query    string
+		expected []int64
+	}{
+		{query: `status:todo`, expected: []int64{1, 3}},
+		{query: `status:review`, expected: []int64{4}},
+		{query: `status!=todo`, expected: []int64{2, 4}},
+		{query: `tag:work`, expected: []int64{1, 2}},
+		{query: `priority>=high`, expected: []int64{1, 4}},
+		{query: `status:todo and (tag:work or priority>=high)`, expected: []int64{1}},
+		{query: `status:todo and (tag:work or priority>=high) and created>-7d and desc~"deploy"`, expected: []int64{1}},
+		{query: `desc:deploy not tag:work`, expected: []int64{4}},
+		{query: `tag:work or status:review and priority:urgent`, expected: []int64{1, 2, 4}},
+		{query: `created<-7d`, expected: []int64{2}},
+		{query: `due:today`, expected: []int64{3}},
+		{query: `due<=today`, expected: []int64{1, 3}},
+		{query: `due>yesterday`, expected: []int64{3}},
+		{query: `due<today`, expected: []int64{1}},
+		{query: `due<"today 13:00"`, expected: []int64{1, 3}},
+		{query: `due:none`, expected: []int64{2, 4}},
+		{query: `is:overdue`, expected: []int64{1}},
+		{query: `is:blocked`, expected: []int64{4}},
+		{query: `project:website`, expected: []int64{2}},
+		{query: `project:none and parent:none`, expected: []int64{1, 3}},
+		{query: `parent=1`, expected: []int64{4}},
+		{query: `estimate<=1h`, expected: []int64{3}},
+		{query: `customer:acme`, expected: []int64{2}},
+		{query: `customer~ac`, expected: []int64{2}},
+		{query: `points>2 and points<5`, expected: []int64{2}},
+		{query: `customer=""`, expected: []int64{1, 3, 4}},
+		{query: `id>=3`, expected: []int64{3, 4}},
+	}
+
+	for _, tst := range tests {
+		q, err := Compile(tst.query, ctx)
+		if err != nil {
+			t.Errorf("%s: %v", tst.query, err)
+			continue
+		}
+		var actual []int64
+		for _, tsk := range tasks {
+			if q.Match(tsk, env) {
+				actual = append(actual, tsk.Id)
+			}
+		}
+		if !slices.Equal(actual, tst.expected) {
+			t.Errorf("%s expected %v but got %v", tst.query, tst.expected, actual)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	ctx := Context{Workflow: task.DefaultWorkflow()}
+
+	tests := []struct {
+		query       string
+		expectedPos int
+		expectedMsg string
+	}{
+		{query: ``, expectedPos: 0, expectedMsg: "empty query"},
+		{query: `status:todo and priority>=hihg`, expectedPos: 26, expectedMsg: "invalid priority"},
+		{query: `status:waiting`, expectedPos: 7, expectedMsg: "unknown status"},
+		{query: `colour:red`, expectedPos: 0, expectedMsg: `unknown field "colour"`},
+		{query: `status<todo`, expectedPos: 6, expectedMsg: `operator "<" can't be used with status`},
+		{query: `(tag:work or tag:home`, expectedPos: 21, expectedMsg: `expected ")"`},
+		{query: `tag:work)`, expectedPos: 8, expectedMsg: `unexpected ")"`},
+		{query: `tag:work and`, expectedPos: 12, expectedMsg: "expected a comparison"},
+		{query: `tag work`, expectedPos: 4, expectedMsg: `expected an operator after "tag"`},
+		{query: `due>someday`, expectedPos: 4, expectedMsg: "unrecognized date"},
+		{query: `is:later`, expectedPos: 3, expectedMsg: "unknown view"},
+	}
+
+	for _, tst := range tests {
+		_, err := Compile(tst.query, ctx)
+		var qerr *Error
+		if !errors.As(err, &qerr) {
+			t.Errorf("%q expected a query error but got %v", tst.query, err)
+			continue
+		}
+		if qerr.Pos != tst.expectedPos || !strings.Contains(qerr.Msg, tst.expectedMsg) {
+			t.Errorf("%q expected %q at %d but got %q at %d", tst.query, tst.expectedMsg, tst.expectedPos, qerr.Msg, qerr.Pos)
+		}
+	}
+
+	// The caret sits under the offending token
+	_, err := Compile(`status:todo and priority>=hihg`, ctx)
+	var qerr *Error
+	if errors.As(err, &qerr) {
+		expected := "status:todo and priority>=hihg\n                          ^"
+		if qerr.Caret() != expected {
+			t.Errorf("expected caret\n%s\nbut got\n%s", expected, qerr.Caret())
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ViewBlocked is anything not done that waits on an unfinished task
+	ViewBlocked View = "blocked"
+	// ViewReady is anything not done with nothing left to wait on
+	ViewReady View = "ready"
+)
+
+var (
+	ErrBlocked         = errors.New("task is blocked")
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	ErrNotBlockedBy    = errors.New("task is not blocked by that task")
+	ErrSelfDependency  = errors.New("task can't block itself")
+)
+
+// WithForce lets Mark start a task even though its blockers are unfinished
+func WithForce() MutationOption {
+	return func(cfg *mutationConfig) {
+		cfg.force = true
+	}
+}
+
+// Block records that task id can't start until task on is done
+func (s TaskService) Block(id, on int64) error {
+	if id == on {
+		return fmt.Errorf("%w with id %d", ErrSelfDependency, id)
+	}
+	return s.mutate(EventBlock, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := indexOf(tasks, on); err != nil {
+			return nil, err
+		}
+		if slices.Contains(tasks[i].BlockedBy, on) {
+			return tasks, nil
+		}
+		if path := dependencyPath(tasks, on, id); path != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, formatPath(append([]int64{id}, path...)))
+		}
+		blockers := append(slices.Clone(tasks[i].BlockedBy), on)
+		slices.Sort(blockers)
+		tasks[i].setBlockers(s.now(), blockers)
+		return tasks, nil
+	})
+}
+
+// Unblock removes the dependency of task id on task on
+func (s TaskService) Unblock(id, on int64) error {
+	return s.mutate(EventUnblock, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(tasks[i].BlockedBy, on) {
+			return nil, fmt.Errorf("%w, %d is not blocked by %d", ErrNotBlockedBy, id, on)
+		}
+		tasks[i].setBlockers(s.now(), slices.DeleteFunc(slices.Clone(tasks[i].BlockedBy), func(b int64) bool { return b == on }))
+		return tasks, nil
+	})
+}
+
+func (t *Task) setBlockers(at time.Time, blockers []int64) {
+	if len(blockers) == 0 {
+		blockers = nil
+	}
+	t.record(at, FieldBlockedBy, joinIDs(t.BlockedBy), joinIDs(blockers))
+	t.BlockedBy = blockers
+	t.UpdatedAt = at
+}
+
+// dependencyPath finds a chain of blockers leading from one task to another,
+// returning nil when there is none. Trashed tasks keep their edges and are
+// walked too, so restoring them can't bring back a cycle
+func dependencyPath(tasks []Task, from, to int64) []int64 {
+	blockedBy := map[int64][]int64{}
+	for _, t := range tasks {
+		blockedBy[t.Id] = t.BlockedBy
+	}
+
+	visited := map[int64]bool{}
+	var walk func(id int64) []int64
+	walk = func(id int64) []int64 {
+		if id == to {
+			return []int64{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, next := range blockedBy[id] {
+			if path := walk(next); path != nil {
+				return append([]int64{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+// openBlockers lists the blockers of a task that are not done yet
+func openBlockers(t Task, tasks map[int64]Task) []int64 {
+	var open []int64
+	for _, id := range t.BlockedBy {
+		if blocker, ok := tasks[id]; ok && !blocker.Trashed() && blocker.Status != StatusDone {
+			open = append(open, id)
+		}
+	}
+	return open
+}
+
+// dropDependencies removes every edge pointing at the given tasks, used when
+// they are purged
+func dropDependencies(tasks []Task, at time.Time, removed map[int64]bool) {
+	for i := range tasks {
+		if !slices.ContainsFunc(tasks[i].BlockedBy, func(id int64) bool { return removed[id] }) {
+			continue
+		}
+		kept := slices.DeleteFunc(slices.Clone(tasks[i].BlockedBy), func(id int64) bool { return removed[id] })
+		tasks[i].setBlockers(at, kept)
+	}
+}
+
+func joinIDs(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(s, ",")
+}
+
+func formatPath(ids []int64) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(s, " -> ")
+}
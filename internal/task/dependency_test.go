@@ -0,0 +1,94 @@
+package task
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDependencies(t *testing.T) {
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(time.Now))
+	for _, desc := range []string{"design", "build", "test", "release"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// release <- test <- build <- design
+	for _, edge := range [][2]int64{{2, 1}, {3, 2}, {4, 3}} {
+		if err := svc.Block(edge[0], edge[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		id, on        int64
+		expectedError error
+	}{
+		{name: "direct cycle", id: 1, on: 2, expectedError: ErrDependencyCycle},
+		{name: "transitive cycle", id: 1, on: 4, expectedError: ErrDependencyCycle},
+		{name: "self", id: 2, on: 2, expectedError: ErrSelfDependency},
+		{name: "missing", id: 2, on: 42, expectedError: ErrNotFound},
+	}
+	for _, tst := range tests {
+		if err := svc.Block(tst.id, tst.on); !errors.Is(err, tst.expectedError) {
+			t.Errorf("%s expected %v but got %v", tst.name, tst.expectedError, err)
+		}
+	}
+
+	blocked := func() ([]Task, error) { return svc.List(ListOptions{View: ViewBlocked}) }
+	ready := func() ([]Task, error) { return svc.List(ListOptions{View: ViewReady}) }
+	assertIDs(t, "blocked", blocked, []int64{2, 3, 4})
+	assertIDs(t, "ready", ready, []int64{1})
+
+	// Starting a blocked task needs force
+	if err := svc.Mark(2, StatusInProgress); !errors.Is(err, ErrBlocked) {
+		t.Errorf("expected %v but got %v", ErrBlocked, err)
+	}
+	if err := svc.Mark(2, StatusInProgress, WithForce()); err != nil {
+		t.Errorf("expected forced start to work but got %v", err)
+	}
+
+	// Finishing a blocker frees the next task
+	if err := svc.Mark(1, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "ready after done", ready, []int64{2})
+
+	// Deleting a task keeps edges pointing at it for a restore, but a trashed
+	// blocker holds nothing up
+	if err := svc.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "ready after delete", ready, []int64{2, 4})
+	if err := svc.Restore(3); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "blocked after restore", blocked, []int64{3, 4})
+
+	// Purging is what cleans up edges pointing at a task
+	if err := svc.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Purge(0); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks {
+		if slices.Contains(task.BlockedBy, 3) {
+			t.Errorf("expected edge to purged task 3 to be removed from task %d", task.Id)
+		}
+	}
+
+	if err := svc.Unblock(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Unblock(2, 1); !errors.Is(err, ErrNotBlockedBy) {
+		t.Errorf("expected %v but got %v", ErrNotBlockedBy, err)
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import "time"
+
+const (
+	// ViewOverdue is anything not done that was due before today
+	ViewOverdue View = "overdue"
+	// ViewToday is anything not done that is due at some point today
+	ViewToday View = "today"
+	// ViewUpcoming is anything not done that is due after today
+	ViewUpcoming View = "upcoming"
+)
+
+func matchDue(v View, t Task, now time.Time) bool {
+	if t.Due.IsZero() || t.Status == StatusDone {
+		return false
+	}
+	today := startOfDay(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	switch v {
+	case ViewOverdue:
+		return t.Due.Before(today)
+	case ViewToday:
+		return !t.Due.Before(today) && t.Due.Before(tomorrow)
+	case ViewUpcoming:
+		return !t.Due.Before(tomorrow)
+	}
+	return false
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// SetDue sets when a task must be finished by, a zero time clears it
+func (s TaskService) SetDue(id int64, due time.Time) error {
+	return s.mutate(EventDue, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if tasks[i].Due.Equal(due) {
+			return tasks, nil
+		}
+		now := s.now()
+		tasks[i].record(now, FieldDue, formatDue(tasks[i].Due), formatDue(due))
+		tasks[i].Due = due
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
+
+func formatDue(due time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	return due.Format(time.RFC3339)
+}
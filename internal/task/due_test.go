@@ -0,0 +1,48 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListDueViews(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2025-12-12T15:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	for _, task := range []Task{
+		{Description: "overdue", Due: now.AddDate(0, 0, -2)},
+		{Description: "overdue but done", Due: now.AddDate(0, 0, -2), Status: StatusDone},
+		{Description: "due this morning", Due: timeMustParse(time.RFC3339, "2025-12-12T09:00:00Z")},
+		{Description: "due tonight", Due: timeMustParse(time.RFC3339, "2025-12-12T23:59:00Z")},
+		{Description: "due tomorrow", Due: timeMustParse(time.RFC3339, "2025-12-13T00:00:00Z")},
+		{Description: "no due date"},
+	} {
+		if _, err := svc.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		view     View
+		expected []int64
+	}{
+		{view: ViewOverdue, expected: []int64{1}},
+		{view: ViewToday, expected: []int64{3, 4}},
+		{view: ViewUpcoming, expected: []int64{5}},
+		{view: "", expected: []int64{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tst := range tests {
+		list := func() ([]Task, error) { return svc.List(ListOptions{View: tst.view}) }
+		assertIDs(t, string(tst.view), list, tst.expected)
+	}
+
+	// Moving the due date moves the task between views
+	if err := svc.SetDue(6, now.AddDate(0, 0, -1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetDue(1, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	overdue := func() ([]Task, error) { return svc.List(ListOptions{View: ViewOverdue}) }
+	assertIDs(t, "overdue after SetDue", overdue, []int64{6})
+}
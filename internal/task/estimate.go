@@ -0,0 +1,160 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidEstimate = errors.New("invalid estimate")
+
+// Estimate is how long a task is expected to take. It is stored as a duration
+// string like "2h" or "1h30m"
+type Estimate time.Duration
+
+func ParseEstimate(s string) (Estimate, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w %q, expected a duration like 30m or 2h", ErrInvalidEstimate, s)
+	}
+	return Estimate(d), nil
+}
+
+// String drops zero minutes and seconds, e.g. "2h" rather than "2h0m0s"
+func (e Estimate) String() string {
+	if e == 0 {
+		return ""
+	}
+	s := time.Duration(e).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func (e Estimate) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+func (e *Estimate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*e = 0
+		return nil
+	}
+	parsed, err := ParseEstimate(string(text))
+	if err != nil {
+		return err
+	}
+	*e = parsed
+	return nil
+}
+
+// SetEstimate sets how long a task is expected to take, zero clears it
+func (s TaskService) SetEstimate(id int64, e Estimate) error {
+	if e < 0 {
+		return fmt.Errorf("%w %s", ErrInvalidEstimate, time.Duration(e))
+	}
+	return s.mutate(EventEstimate, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if tasks[i].Estimate == e {
+			return tasks, nil
+		}
+		now := s.now()
+		tasks[i].record(now, FieldEstimate, tasks[i].Estimate.String(), e.String())
+		tasks[i].Estimate = e
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
+
+// Actual is how long a finished task took, from the first time it was marked
+// in progress to the last time it was marked done
+func (t Task) Actual() (time.Duration, bool) {
+	if t.Status != StatusDone {
+		return 0, false
+	}
+	var started, finished time.Time
+	for _, c := range t.History {
+		if c.Field != FieldStatus {
+			continue
+		}
+		switch c.To {
+		case StatusInProgress.String():
+			if started.IsZero() {
+				started = c.At
+			}
+		case StatusDone.String():
+			if !started.IsZero() {
+				finished = c.At
+			}
+		}
+	}
+	if finished.IsZero() {
+		return 0, false
+	}
+	return finished.Sub(started), true
+}
+
+// EstimateReport compares estimates with the actual time taken by finished
+// tasks that have both
+type EstimateReport struct {
+	Tasks     []EstimateComparison
+	Estimated time.Duration
+	Actual    time.Duration
+}
+
+type EstimateComparison struct {
+	Task     Task
+	Estimate time.Duration
+	Actual   time.Duration
+}
+
+// Ratio of actual to estimated time, above 1 means it took longer than
+// expected
+func (c EstimateComparison) Ratio() float64 {
+	return float64(c.Actual) / float64(c.Estimate)
+}
+
+// Ratio of actual to estimated time over every task in the report, zero when
+// the report is empty
+func (r EstimateReport) Ratio() float64 {
+	if r.Estimated == 0 {
+		return 0
+	}
+	return float64(r.Actual) / float64(r.Estimated)
+}
+
+func (s TaskService) EstimateReport() (EstimateReport, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return EstimateReport{}, err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil {
+		return EstimateReport{}, err
+	}
+
+	var report EstimateReport
+	for _, t := range tasks {
+		if t.Trashed() || t.Estimate == 0 {
+			continue
+		}
+		actual, ok := t.Actual()
+		if !ok {
+			continue
+		}
+		report.Tasks = append(report.Tasks, EstimateComparison{Task: t, Estimate: time.Duration(t.Estimate), Actual: actual})
+		report.Estimated += time.Duration(t.Estimate)
+		report.Actual += actual
+	}
+	return report, nil
+}
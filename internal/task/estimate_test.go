@@ -0,0 +1,124 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		falsy    bool
+	}{
+		{input: "2h", expected: "2h"},
+		{input: "30m", expected: "30m"},
+		{input: "90m", expected: "1h30m"},
+		{input: "45s", expected: "45s"},
+		{input: "0m", falsy: true},
+		{input: "-1h", falsy: true},
+		{input: "two hours", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := ParseEstimate(tst.input)
+		if tst.falsy {
+			if !errors.Is(err, ErrInvalidEstimate) {
+				t.Errorf("%q expected %v but got %v", tst.input, ErrInvalidEstimate, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if actual.String() != tst.expected {
+			t.Errorf("%q expected %s but got %s", tst.input, tst.expected, actual.String())
+		}
+	}
+}
+
+func TestEstimateJSON(t *testing.T) {
+	data, err := json.Marshal(Task{Id: 1, Estimate: Estimate(90 * time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tsk Task
+	if err := json.Unmarshal(data, &tsk); err != nil {
+		t.Fatal(err)
+	}
+	if tsk.Estimate != Estimate(90*time.Minute) {
+		t.Errorf("expected 1h30m to survive a round trip through %s but got %s", data, tsk.Estimate)
+	}
+
+	data, err = json.Marshal(Task{Id: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["estimate"]; ok {
+		t.Errorf("expected no estimate to be left out but got %s", data)
+	}
+}
+
+func TestEstimateReport(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	for _, desc := range []string{"Estimated and done", "Estimated but open", "Done without an estimate"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.SetEstimate(1, Estimate(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetEstimate(2, Estimate(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Time before the first start does not count, reopening keeps the first start
+	now = now.Add(time.Hour)
+	for _, id := range []int64{1, 2, 3} {
+		if err := svc.Mark(id, StatusInProgress); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Hour)
+	if err := svc.Mark(1, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(1, StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	for _, id := range []int64{1, 3} {
+		if err := svc.Mark(id, StatusDone); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := svc.EstimateReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Tasks) != 1 || report.Tasks[0].Task.Id != 1 {
+		t.Fatalf("expected only task 1 in the report but got %+v", report.Tasks)
+	}
+	if report.Tasks[0].Actual != 3*time.Hour || report.Ratio() != 1.5 {
+		t.Errorf("expected 3h taken at a ratio of 1.5 but got %v at %v", report.Tasks[0].Actual, report.Ratio())
+	}
+
+	history, err := svc.History(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := history[1]; c.Field != FieldEstimate || c.To != "1h" {
+		t.Errorf("expected the estimate to be recorded in history but got %+v", c)
+	}
+}
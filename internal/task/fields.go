@@ -0,0 +1,276 @@
+package task
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/dateparse"
+)
+
+var (
+	ErrInvalidSchema     = errors.New("invalid field schema")
+	ErrUnknownField      = errors.New("unknown field")
+	ErrInvalidFieldValue = errors.New("invalid field value")
+)
+
+// FieldType is the kind of value a custom field holds
+type FieldType string
+
+const (
+	TypeString FieldType = "string"
+	TypeNumber FieldType = "number"
+	TypeDate   FieldType = "date"
+	// TypeEnum only accepts one of the field's Values
+	TypeEnum FieldType = "enum"
+)
+
+// FieldDef declares a custom field that can be set on any task
+type FieldDef struct {
+	Name   string
+	Type   FieldType
+	Values []string
+}
+
+// Schema is the set of custom fields tasks may carry. Values are stored in
+// Task.Fields as strings, except numbers which are float64, and dates which
+// are RFC 3339 strings
+type Schema struct {
+	defs []FieldDef
+}
+
+// reservedFields can't be used for custom fields so they never shadow a built
+// in one in filters, sort keys or the history
+var reservedFields = []string{
+	"id", "description", "desc", "status", "priority", "tag", "tags", "project", "parent", "is",
+	FieldBlockedBy, FieldRecurrence, FieldEstimate, "due", "created", "updated", "deleted",
+}
+
+func NewSchema(defs ...FieldDef) (Schema, error) {
+	var s Schema
+	for _, def := range defs {
+		def.Name = strings.ToLower(strings.TrimSpace(def.Name))
+		if def.Name == "" || strings.ContainsFunc(def.Name, isSpaceOrPlus) || strings.ContainsAny(def.Name, "=<>!") {
+			return Schema{}, fmt.Errorf("%w: field %q must be a single word", ErrInvalidSchema, def.Name)
+		}
+		if slices.ContainsFunc(reservedFields, func(r string) bool { return strings.EqualFold(r, def.Name) }) {
+			return Schema{}, fmt.Errorf("%w: field %q is built in", ErrInvalidSchema, def.Name)
+		}
+		if _, ok := s.Lookup(def.Name); ok {
+			return Schema{}, fmt.Errorf("%w: field %q is declared twice", ErrInvalidSchema, def.Name)
+		}
+		switch def.Type {
+		case TypeString, TypeNumber, TypeDate:
+		case TypeEnum:
+			if len(def.Values) == 0 {
+				return Schema{}, fmt.Errorf("%w: enum field %q has no values", ErrInvalidSchema, def.Name)
+			}
+		default:
+			return Schema{}, fmt.Errorf("%w: field %q has unknown type %q, expected string, number, date or enum", ErrInvalidSchema, def.Name, def.Type)
+		}
+		def.Values = slices.Clone(def.Values)
+		s.defs = append(s.defs, def)
+	}
+	return s, nil
+}
+
+// Fields in the order they were declared
+func (s Schema) Fields() []FieldDef {
+	return slices.Clone(s.defs)
+}
+
+func (s Schema) Lookup(name string) (FieldDef, bool) {
+	for _, def := range s.defs {
+		if strings.EqualFold(def.Name, name) {
+			return def, true
+		}
+	}
+	return FieldDef{}, false
+}
+
+func (s Schema) lookup(name string) (FieldDef, error) {
+	def, ok := s.Lookup(name)
+	if !ok {
+		return FieldDef{}, fmt.Errorf("%w %q", ErrUnknownField, name)
+	}
+	return def, nil
+}
+
+// parse turns typed text into the value stored on a task, dates may be
+// anything dateparse understands
+func (d FieldDef) parse(text string, now time.Time) (any, error) {
+	text = strings.TrimSpace(text)
+	switch d.Type {
+	case TypeNumber:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q for %s, expected a number", ErrInvalidFieldValue, text, d.Name)
+		}
+		return f, nil
+	case TypeDate:
+		t, err := dateparse.Parse(text, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q for %s, expected a date", ErrInvalidFieldValue, text, d.Name)
+		}
+		return t.Format(time.RFC3339), nil
+	case TypeEnum:
+		for _, v := range d.Values {
+			if strings.EqualFold(v, text) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%w %q for %s, expected one of %s", ErrInvalidFieldValue, text, d.Name, strings.Join(d.Values, ", "))
+	}
+	return text, nil
+}
+
+// Format writes a stored value back out as text
+func (d FieldDef) Format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// compare orders two stored values, numbers and dates by magnitude, enums in
+// the order of their values and strings alphabetically
+func (d FieldDef) compare(a, b any) int {
+	switch d.Type {
+	case TypeNumber:
+		x, _ := a.(float64)
+		y, _ := b.(float64)
+		return cmp.Compare(x, y)
+	case TypeDate:
+		x, _ := time.Parse(time.RFC3339, d.Format(a))
+		y, _ := time.Parse(time.RFC3339, d.Format(b))
+		return x.Compare(y)
+	case TypeEnum:
+		return cmp.Compare(slices.Index(d.Values, d.Format(a)), slices.Index(d.Values, d.Format(b)))
+	}
+	return strings.Compare(strings.ToLower(d.Format(a)), strings.ToLower(d.Format(b)))
+}
+
+// WithSchema declares the custom fields tasks may carry
+func WithSchema(schema Schema) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.schema = schema
+	}
+}
+
+func (s TaskService) Schema() Schema {
+	return s.schema
+}
+
+// SetFields sets custom fields on a task in one change, an empty value clears
+// the field
+func (s TaskService) SetFields(id int64, values map[string]string) error {
+	return s.mutate(EventSet, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		now := s.now()
+		for _, name := range slices.Sorted(maps.Keys(values)) {
+			def, err := s.schema.lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			var value any
+			if strings.TrimSpace(values[name]) != "" {
+				if value, err = def.parse(values[name], now); err != nil {
+					return nil, err
+				}
+			}
+
+			before := def.Format(tasks[i].Fields[def.Name])
+			if before == def.Format(value) {
+				continue
+			}
+			tasks[i].record(now, def.Name, before, def.Format(value))
+			if value == nil {
+				delete(tasks[i].Fields, def.Name)
+			} else {
+				if tasks[i].Fields == nil {
+					tasks[i].Fields = map[string]any{}
+				}
+				tasks[i].Fields[def.Name] = value
+			}
+			tasks[i].UpdatedAt = now
+		}
+		return tasks, nil
+	})
+}
+
+// FieldFilter compares a custom field against a value, Op is one of =, !=, <,
+// <=, > or >=
+type FieldFilter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+var filterOps = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// fieldMatcher is a FieldFilter checked against the schema with its value
+// parsed
+type fieldMatcher struct {
+	def   FieldDef
+	op    string
+	value any
+}
+
+// Filter checks a FieldFilter against the schema and parses its value
+func (s Schema) Filter(f FieldFilter, now time.Time) (Matcher, error) {
+	def, err := s.lookup(f.Field)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(filterOps, f.Op) {
+		return nil, fmt.Errorf("%w operator %q for %s", ErrInvalidFieldValue, f.Op, f.Field)
+	}
+	m := fieldMatcher{def: def, op: f.Op}
+	if strings.TrimSpace(f.Value) != "" {
+		if m.value, err = def.parse(f.Value, now); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Match treats an empty filter value as "not set", so "customer=" finds tasks
+// without a customer. Tasks without the field never pass an ordering filter
+func (m fieldMatcher) Match(t Task, _ Env) bool {
+	v, ok := t.Fields[m.def.Name]
+	if m.value == nil {
+		return ok == (m.op == "!=")
+	}
+	if !ok {
+		return m.op == "!="
+	}
+	c := m.def.compare(v, m.value)
+	switch m.op {
+	case "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
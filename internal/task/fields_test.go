@@ -0,0 +1,103 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		defs          []FieldDef
+		expectedError error
+	}{
+		{name: "valid", defs: []FieldDef{{Name: "Ticket", Type: TypeString}, {Name: "env", Type: TypeEnum, Values: []string{"dev", "prod"}}}},
+		{name: "builtin", defs: []FieldDef{{Name: "due", Type: TypeDate}}, expectedError: ErrInvalidSchema},
+		{name: "duplicate", defs: []FieldDef{{Name: "ticket", Type: TypeString}, {Name: "TICKET", Type: TypeNumber}}, expectedError: ErrInvalidSchema},
+		{name: "emptyEnum", defs: []FieldDef{{Name: "env", Type: TypeEnum}}, expectedError: ErrInvalidSchema},
+		{name: "unknownType", defs: []FieldDef{{Name: "size", Type: "bool"}}, expectedError: ErrInvalidSchema},
+		{name: "operator", defs: []FieldDef{{Name: "a=b", Type: TypeString}}, expectedError: ErrInvalidSchema},
+	}
+
+	for _, tst := range tests {
+		_, err := NewSchema(tst.defs...)
+		if !errors.Is(err, tst.expectedError) {
+			t.Errorf("%s expected %v but got %v", tst.name, tst.expectedError, err)
+		}
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	schema, err := NewSchema(
+		FieldDef{Name: "customer", Type: TypeString},
+		FieldDef{Name: "points", Type: TypeNumber},
+		FieldDef{Name: "deadline", Type: TypeDate},
+		FieldDef{Name: "env", Type: TypeEnum, Values: []string{"dev", "staging", "prod"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithSchema(schema), WithTimeFunction(func() time.Time { return now }))
+
+	for _, desc := range []string{"Fix invoices", "Migrate database", "Update logo"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	values := map[int64]map[string]string{
+		1: {"customer": "Acme", "points": "5", "env": "PROD", "deadline": "tomorrow"},
+		2: {"customer": "Globex", "points": "8", "env": "staging"},
+		3: {"points": "1.5", "env": "dev"},
+	}
+	for id, v := range values {
+		if err := svc.SetFields(id, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := svc.SetFields(1, map[string]string{"points": "lots"}); !errors.Is(err, ErrInvalidFieldValue) {
+		t.Errorf("expected %v but got %v", ErrInvalidFieldValue, err)
+	}
+	if err := svc.SetFields(1, map[string]string{"env": "qa"}); !errors.Is(err, ErrInvalidFieldValue) {
+		t.Errorf("expected %v but got %v", ErrInvalidFieldValue, err)
+	}
+	if err := svc.SetFields(1, map[string]string{"ticket": "ABC-1"}); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("expected %v but got %v", ErrUnknownField, err)
+	}
+
+	task, err := svc.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Fields["env"] != "prod" || task.Fields["points"] != 5.0 || task.Fields["deadline"] != "2026-01-15T00:00:00Z" {
+		t.Errorf("expected canonical values but got %v", task.Fields)
+	}
+
+	list := func(opts ListOptions) func() ([]Task, error) {
+		return func() ([]Task, error) { return svc.List(opts) }
+	}
+	assertIDs(t, "customer", list(ListOptions{Fields: []FieldFilter{{Field: "customer", Op: "=", Value: "acme"}}}), []int64{1})
+	assertIDs(t, "no customer", list(ListOptions{Fields: []FieldFilter{{Field: "customer", Op: "=", Value: ""}}}), []int64{3})
+	assertIDs(t, "points", list(ListOptions{Fields: []FieldFilter{{Field: "points", Op: ">", Value: "2"}}}), []int64{1, 2})
+	assertIDs(t, "env", list(ListOptions{Fields: []FieldFilter{{Field: "env", Op: ">=", Value: "staging"}}}), []int64{1, 2})
+	assertIDs(t, "deadline", list(ListOptions{Fields: []FieldFilter{{Field: "deadline", Op: "<", Value: "in 3 days"}}}), []int64{1})
+	assertIDs(t, "sort points", list(ListOptions{Sort: []SortKey{{Field: "points", Desc: true}}}), []int64{2, 1, 3})
+	assertIDs(t, "sort customer", list(ListOptions{Sort: []SortKey{{Field: "customer"}}}), []int64{1, 2, 3})
+	if _, err := svc.List(ListOptions{Sort: []SortKey{{Field: "ticket"}}}); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("expected %v but got %v", ErrUnknownField, err)
+	}
+
+	// Clearing a field removes it and is kept in the history
+	if err := svc.SetFields(1, map[string]string{"customer": ""}); err != nil {
+		t.Fatal(err)
+	}
+	history, err := svc.History(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := history[len(history)-1]; c.Field != "customer" || c.From != "Acme" || c.To != "" {
+		t.Errorf("expected the cleared customer in the history but got %+v", c)
+	}
+}
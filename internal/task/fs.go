@@ -3,22 +3,38 @@ package task
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
 )
 
 var ErrFileNotExist = os.ErrNotExist
 
+// ErrRecovered comes with the tasks read from the .bak file when the main file
+// cannot be parsed, whatever the last save changed is lost
+var ErrRecovered = errors.New("task file is corrupt, recovered the previous save from")
+
+// save writes the tasks to a temp file next to savePath and renames it over the
+// original, keeping the previous version in a .bak file
 func save(savePath string, tasks []Task) error {
 	js, err := json.Marshal(tasks)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(savePath, js, 0644); err != nil {
-		return err
+
+	// Only back up the previous version if it is intact, so a corrupt main file
+	// never clobbers a good backup
+	if previous, err := os.ReadFile(savePath); err == nil && json.Valid(previous) {
+		if err := WriteFileAtomic(backupPath(savePath), previous); err != nil {
+			return err
+		}
 	}
-	return nil
+	return WriteFileAtomic(savePath, js)
 }
 
+// load reads the tasks from savePath, falling back to the .bak file when the
+// main file cannot be parsed. A fallback returns the backup tasks together
+// with ErrRecovered
 func load(savePath string) ([]Task, error) {
 	bytes, err := os.ReadFile(savePath)
 	if err != nil {
@@ -26,12 +42,76 @@ func load(savePath string) ([]Task, error) {
 	}
 	var tasks []Task
 	if err := json.Unmarshal(bytes, &tasks); err != nil {
-		return nil, err
+		backup, backupErr := loadBackup(savePath)
+		if backupErr != nil {
+			return nil, err
+		}
+		return backup, fmt.Errorf("%w %s: %w", ErrRecovered, backupPath(savePath), err)
 	}
 
 	return tasks, nil
 }
 
+func loadBackup(savePath string) ([]Task, error) {
+	bytes, err := os.ReadFile(backupPath(savePath))
+	if err != nil {
+		return nil, err
+	}
+	var tasks []Task
+	if err := json.Unmarshal(bytes, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func backupPath(savePath string) string {
+	return savePath + ".bak"
+}
+
+// WriteFileAtomic writes to a synced temp file in the same directory and
+// renames it into place so readers never see a partial file. Other files the
+// CLI rewrites, such as its config, use it too
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Clean up the temp file on any failure, this is a no-op after the rename
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir flushes the directory entry after a rename. Not every platform
+// supports syncing a directory so failures are ignored
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	_ = d.Sync()
+}
+
 // uses os.Remove but fails silently if path is not found
 func deleteFile(path string) error {
 	err := os.Remove(path)
@@ -2,6 +2,7 @@ package task
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -124,6 +125,124 @@ func TestLoad(t *testing.T) {
 	}
 }
 
+func TestSaveKeepsBackup(t *testing.T) {
+	fileName := "tst-TestSaveKeepsBackup.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			os.Remove(f)
+		}
+	})
+
+	first := []Task{{Id: 1, Description: "First version", CreatedAt: timeMustParse(time.RFC3339, "2025-12-12T13:13:59Z")}}
+	second := []Task{{Id: 1, Description: "Second version", CreatedAt: timeMustParse(time.RFC3339, "2025-12-12T13:13:59Z")}}
+	if err := save(fileName, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := save(fileName, second); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := loadBackup(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isTasksSame(backup, first) {
+		t.Errorf("expected backup %v but got %v", first, backup)
+	}
+	actual, err := load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isTasksSame(actual, second) {
+		t.Errorf("expected %v but got %v", second, actual)
+	}
+}
+
+func TestLoadRecoversFromBackup(t *testing.T) {
+	tests := []struct {
+		name              string
+		testFileName      string
+		testFileContent   string
+		backupFileContent string
+		expected          []Task
+		expectError       bool
+		expectRecovered   bool
+	}{
+		{
+			name:              "Should recover truncated file from backup",
+			testFileName:      "tst-TestLoadRecoversTruncated.json",
+			testFileContent:   `[{"id": 1, "descrip`,
+			backupFileContent: `[{"id": 1, "description": "Test the backup", "status": 2, "createdAt": "2025-12-12T13:13:59Z"}]`,
+			expected: []Task{{
+				Id:          1,
+				Description: "Test the backup",
+				Status:      StatusDone,
+				CreatedAt:   timeMustParse(time.RFC3339, "2025-12-12T13:13:59Z"),
+			}},
+			expectRecovered: true,
+		},
+		{
+			name:              "Should error when both files are corrupt",
+			testFileName:      "tst-TestLoadRecoversBothCorrupt.json",
+			testFileContent:   `[{"id": 1, "descrip`,
+			backupFileContent: `[{"id": 1, "desc`,
+			expectError:       true,
+		},
+		{
+			name:            "Should error when corrupt without a backup",
+			testFileName:    "tst-TestLoadRecoversNoBackup.json",
+			testFileContent: `[{"id": 1, "descrip`,
+			expectError:     true,
+		},
+	}
+
+	t.Cleanup(func() {
+		for _, tst := range tests {
+			for _, f := range testFiles(tst.testFileName) {
+				os.Remove(f)
+			}
+		}
+	})
+	for _, tst := range tests {
+		if err := os.WriteFile(tst.testFileName, []byte(tst.testFileContent), 0644); err != nil {
+			t.Fatalf("failed to prepare test file: %s", err.Error())
+		}
+		if tst.backupFileContent != "" {
+			if err := os.WriteFile(backupPath(tst.testFileName), []byte(tst.backupFileContent), 0644); err != nil {
+				t.Fatalf("failed to prepare backup file: %s", err.Error())
+			}
+		}
+
+		actual, err := load(tst.testFileName)
+		if tst.expectError {
+			if err == nil || errors.Is(err, ErrRecovered) {
+				t.Errorf("%s expected an error but got %v", tst.name, actual)
+			}
+			continue
+		}
+		if tst.expectRecovered != errors.Is(err, ErrRecovered) {
+			t.Errorf("%s expected recovered %v but got %v", tst.name, tst.expectRecovered, err)
+		}
+		if !isTasksSame(actual, tst.expected) {
+			t.Errorf("%s expected %v but got %v", tst.name, tst.expected, actual)
+		}
+
+		// The store hands out the recovered tasks and keeps a warning for the user
+		store := NewFileStore(tst.testFileName)
+		if _, err := store.Load(); err != nil {
+			t.Error(err)
+		}
+		if warnings := store.(Warner).Warnings(); len(warnings) != 1 || !errors.Is(warnings[0], ErrRecovered) {
+			t.Errorf("%s expected a recovered warning but got %v", tst.name, warnings)
+		}
+	}
+}
+
+// Every file the service may create for a given save path
+func testFiles(fileName string) []string {
+	return []string{fileName, backupPath(fileName), lockPath(fileName), journalPath(fileName), projectsPath(fileName), indexPath(fileName)}
+}
+
 func isTasksSame(t1, t2 []Task) bool {
 	if len(t1) != len(t2) {
 		return false
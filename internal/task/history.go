@@ -0,0 +1,82 @@
+package task
+
+import "time"
+
+// Change is one entry in a task's audit trail. A status change with no From
+// marks the task being created
+type Change struct {
+	At    time.Time `json:"at"`
+	Field string    `json:"field"`
+	From  string    `json:"from,omitempty"`
+	To    string    `json:"to,omitempty"`
+}
+
+const (
+	FieldDescription = "description"
+	FieldStatus      = "status"
+	FieldPriority    = "priority"
+	FieldDue         = "due"
+	FieldTags        = "tags"
+	FieldBlockedBy   = "blockedBy"
+	FieldRecurrence  = "recurrence"
+	FieldEstimate    = "estimate"
+	FieldProject     = "project"
+)
+
+// StatusSpan is the total time a task spent in one status
+type StatusSpan struct {
+	Status   string
+	Duration time.Duration
+}
+
+// TimeInStatus adds up how long the task sat in each status, counting the
+// current status up until the given time. Statuses are in the order they were
+// first entered
+func TimeInStatus(history []Change, until time.Time) []StatusSpan {
+	var spans []StatusSpan
+	indexes := map[string]int{}
+	current := ""
+	var since time.Time
+	add := func(status string, d time.Duration) {
+		i, ok := indexes[status]
+		if !ok {
+			i = len(spans)
+			indexes[status] = i
+			spans = append(spans, StatusSpan{Status: status})
+		}
+		spans[i].Duration += d
+	}
+
+	for _, c := range history {
+		if c.Field != FieldStatus {
+			continue
+		}
+		if current != "" {
+			add(current, c.At.Sub(since))
+		}
+		current = c.To
+		since = c.At
+	}
+	if current != "" {
+		add(current, until.Sub(since))
+	}
+	return spans
+}
+
+func (s TaskService) History(id int64) ([]Change, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	t, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.History, nil
+}
+
+func (t *Task) record(at time.Time, field, from, to string) {
+	t.History = append(t.History, Change{At: at, Field: field, From: from, To: to})
+}
@@ -0,0 +1,76 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	created := timeMustParse(time.RFC3339, "2025-12-12T09:00:00Z")
+	now := created
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	id, err := svc.Add(Task{Description: "Test the history"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := svc.Update(id, Task{Description: "Test the history changes"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(id, StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	// Marking with the same status is not a change
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := svc.History(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{At: created, Field: FieldStatus, To: "todo"},
+		{At: created.Add(time.Hour), Field: FieldDescription, From: "Test the history", To: "Test the history changes"},
+		{At: created.Add(time.Hour), Field: FieldStatus, From: "todo", To: "in-progress"},
+		{At: created.Add(3 * time.Hour), Field: FieldStatus, From: "in-progress", To: "done"},
+	}
+	if len(history) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, history)
+	}
+	for i := range expected {
+		if !history[i].At.Equal(expected[i].At) || history[i].Field != expected[i].Field || history[i].From != expected[i].From || history[i].To != expected[i].To {
+			t.Errorf("expected change %d to be %v but got %v", i, expected[i], history[i])
+		}
+	}
+}
+
+func TestTimeInStatus(t *testing.T) {
+	start := timeMustParse(time.RFC3339, "2025-12-12T09:00:00Z")
+	history := []Change{
+		{At: start, Field: FieldStatus, To: "todo"},
+		{At: start.Add(time.Hour), Field: FieldStatus, From: "todo", To: "in-progress"},
+		{At: start.Add(90 * time.Minute), Field: FieldDescription, From: "a", To: "b"},
+		{At: start.Add(2 * time.Hour), Field: FieldStatus, From: "in-progress", To: "todo"},
+		{At: start.Add(4 * time.Hour), Field: FieldStatus, From: "todo", To: "in-progress"},
+	}
+
+	spans := TimeInStatus(history, start.Add(5*time.Hour))
+	expected := []StatusSpan{
+		{Status: "todo", Duration: 3 * time.Hour},
+		{Status: "in-progress", Duration: 2 * time.Hour},
+	}
+	if len(spans) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, spans)
+	}
+	for i := range expected {
+		if spans[i] != expected[i] {
+			t.Errorf("expected %v but got %v", expected[i], spans[i])
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventAdd        EventType = "add"
+	EventUpdate     EventType = "update"
+	EventDelete     EventType = "delete"
+	EventMark       EventType = "mark"
+	EventPrioritize EventType = "prioritize"
+	EventDue        EventType = "due"
+	EventTag        EventType = "tag"
+	EventBlock      EventType = "block"
+	EventUnblock    EventType = "unblock"
+	EventRecur      EventType = "recur"
+	EventStart      EventType = "start"
+	EventStop       EventType = "stop"
+	EventEstimate   EventType = "estimate"
+	EventNote       EventType = "note"
+	EventSet        EventType = "set"
+	EventAssign     EventType = "assign"
+	EventRestore    EventType = "restore"
+	EventPurge      EventType = "purge"
+	EventUndo       EventType = "undo"
+	EventRedo       EventType = "redo"
+	EventCompact    EventType = "compact"
+)
+
+const (
+	// maxJournalEvents is how long the journal may grow before it is compacted
+	maxJournalEvents = 2000
+	// undoDepth is how many transactions can still be undone after compacting
+	undoDepth = 100
+)
+
+var (
+	ErrNothingToUndo   = errors.New("nothing to undo")
+	ErrNothingToRedo   = errors.New("nothing to redo")
+	ErrCorruptJournal  = errors.New("corrupt journal")
+	ErrJournalDiverged = errors.New("tasks were changed outside task-cli")
+)
+
+// Event records a single task changing. Every event written by one mutation
+// shares a Tx so undo and redo treat them as a unit. Undo and redo markers
+// point at the Tx they reverted through Ref
+type Event struct {
+	Tx     int64     `json:"tx"`
+	Type   EventType `json:"type"`
+	TaskID int64     `json:"taskId,omitzero"`
+	Before *Task     `json:"before,omitempty"`
+	After  *Task     `json:"after,omitempty"`
+	At     time.Time `json:"at"`
+	Ref    int64     `json:"ref,omitzero"`
+}
+
+// Journal is an append-only log of every mutation made by a TaskService
+type Journal interface {
+	Append(events ...Event) error
+	Events() ([]Event, error)
+}
+
+// Rewriter is implemented by journals that can replace their whole contents,
+// used to roll back a failed save and to compact the journal
+type Rewriter interface {
+	Rewrite(events []Event) error
+}
+
+// fileJournal stores one JSON encoded event per line
+type fileJournal struct {
+	path string
+}
+
+func NewFileJournal(path string) Journal {
+	return fileJournal{path: path}
+}
+
+// Append writes one line per event. A torn last line left by a crash is cut
+// off first so the new events don't get glued onto it
+func (j fileJournal) Append(events ...Event) error {
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if _, valid, err := decodeEvents(data); err != nil {
+		return err
+	} else if valid < len(data) {
+		if err := f.Truncate(int64(valid)); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	if err := encodeEvents(w, events); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func (j fileJournal) Events() ([]Event, error) {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	events, _, err := decodeEvents(data)
+	return events, err
+}
+
+func (j fileJournal) Rewrite(events []Event) error {
+	b := bytes.Buffer{}
+	if err := encodeEvents(&b, events); err != nil {
+		return err
+	}
+	return WriteFileAtomic(j.path, b.Bytes())
+}
+
+func encodeEvents(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeEvents reads one event per line and returns how many bytes hold
+// complete events. Only the last line may be broken, that is a write cut
+// short by a crash and is ignored. A broken line before it is an error
+func decodeEvents(data []byte) ([]Event, int, error) {
+	var events []Event
+	valid := 0
+	for valid < len(data) {
+		line, rest, found := bytes.Cut(data[valid:], []byte("\n"))
+		var e Event
+		err := json.Unmarshal(line, &e)
+		if len(bytes.TrimSpace(line)) == 0 {
+			err = nil
+		} else if err == nil {
+			events = append(events, e)
+		}
+		if err != nil || !found {
+			if len(bytes.TrimSpace(rest)) > 0 {
+				return nil, 0, fmt.Errorf("%w at byte %d: %w", ErrCorruptJournal, valid, err)
+			}
+			if err == nil && !found {
+				// An event missing its newline was cut short too
+				events = events[:len(events)-1]
+			}
+			return events, valid, nil
+		}
+		valid += len(line) + 1
+	}
+	return events, valid, nil
+}
+
+func journalPath(savePath string) string {
+	return savePath + ".journal"
+}
+
+// memoryJournal keeps events in memory only, mostly useful for tests
+type memoryJournal struct {
+	mu     *sync.Mutex
+	events *[]Event
+}
+
+func NewMemoryJournal() Journal {
+	return memoryJournal{mu: &sync.Mutex{}, events: &[]Event{}}
+}
+
+func (j memoryJournal) Append(events ...Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	*j.events = append(*j.events, events...)
+	return nil
+}
+
+func (j memoryJournal) Events() ([]Event, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]Event{}, *j.events...), nil
+}
+
+func (j memoryJournal) Rewrite(events []Event) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	*j.events = append([]Event{}, events...)
+	return nil
+}
+
+// replayStacks walks the journal and works out which transactions are
+// currently applied (done) and which have been undone and can be redone
+func replayStacks(events []Event) (done, undone []int64) {
+	for i, e := range events {
+		// Only look at the first event of each transaction
+		if i > 0 && events[i-1].Tx == e.Tx {
+			continue
+		}
+		switch e.Type {
+		case EventCompact:
+			continue
+		case EventUndo:
+			if len(done) > 0 {
+				done = done[:len(done)-1]
+			}
+			undone = append(undone, e.Ref)
+		case EventRedo:
+			if len(undone) > 0 {
+				undone = undone[:len(undone)-1]
+			}
+			done = append(done, e.Ref)
+		default:
+			done = append(done, e.Tx)
+			undone = nil
+		}
+	}
+	return done, undone
+}
+
+// compactEvents drops all but the last keep undoable transactions. What can
+// still be redone is kept by writing those transactions back followed by
+// their undo markers, so replayStacks gives the same stacks as before. A
+// leading compact event remembers the highest task id for highestID
+func compactEvents(events []Event, keep int) []Event {
+	done, undone := replayStacks(events)
+	if len(done) > keep {
+		done = done[len(done)-keep:]
+	}
+
+	var maxTx, maxID int64
+	var at time.Time
+	for _, e := range events {
+		maxTx = max(maxTx, e.Tx)
+		maxID = max(maxID, e.TaskID)
+		at = e.At
+	}
+	compacted := []Event{{Type: EventCompact, TaskID: maxID, At: at}}
+	for _, tx := range done {
+		compacted = append(compacted, eventsInTx(events, tx)...)
+	}
+	for i := len(undone) - 1; i >= 0; i-- {
+		compacted = append(compacted, eventsInTx(events, undone[i])...)
+	}
+	for _, tx := range undone {
+		maxTx++
+		compacted = append(compacted, Event{Tx: maxTx, Type: EventUndo, At: at, Ref: tx})
+	}
+	return compacted
+}
+
+// unapplied finds a last transaction that never reached the store, which a
+// crash between journaling and saving leaves behind. It returns where that
+// transaction starts in events. Only a store still in the state from before
+// the transaction counts, when it matches neither side the tasks were changed
+// some other way, such as by hand, and diverged is set instead
+func unapplied(events []Event, tasks []Task) (start int, found, diverged bool, err error) {
+	if len(events) == 0 {
+		return 0, false, false, nil
+	}
+	last := events[len(events)-1]
+	start = len(events) - 1
+	for start > 0 && events[start-1].Tx == last.Tx {
+		start--
+	}
+
+	expected, useAfter := events[start:], true
+	switch last.Type {
+	case EventCompact:
+		return 0, false, false, nil
+	case EventUndo:
+		expected, useAfter = eventsInTx(events, last.Ref), false
+	case EventRedo:
+		expected = eventsInTx(events, last.Ref)
+	}
+	applied, err := matchesSnapshots(expected, tasks, useAfter)
+	if err != nil || applied {
+		return 0, false, false, err
+	}
+	notApplied, err := matchesSnapshots(expected, tasks, !useAfter)
+	if err != nil {
+		return 0, false, false, err
+	}
+	if !notApplied {
+		return 0, false, true, nil
+	}
+	return start, true, false, nil
+}
+
+// matchesSnapshots reports whether every task touched by events is in its
+// After state, or its Before state when useAfter is false
+func matchesSnapshots(events []Event, tasks []Task, useAfter bool) (bool, error) {
+	for _, e := range events {
+		snapshot := e.After
+		if !useAfter {
+			snapshot = e.Before
+		}
+		current, err := findTask(tasks, e.TaskID)
+		if snapshot == nil || err != nil {
+			if (snapshot == nil) != (err != nil) {
+				return false, nil
+			}
+			continue
+		}
+		same, err := isSameJSON(current, *snapshot)
+		if err != nil || !same {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func eventsInTx(events []Event, tx int64) []Event {
+	var txEvents []Event
+	for _, e := range events {
+		if e.Tx == tx {
+			txEvents = append(txEvents, e)
+		}
+	}
+	return txEvents
+}
+
+func lastTx(events []Event) int64 {
+	if len(events) == 0 {
+		return 0
+	}
+	return events[len(events)-1].Tx
+}
+
+// diffTasks creates an event for every task that was added, changed or
+// removed between the two lists
+func diffTasks(eventType EventType, before, after []Task) ([]Event, error) {
+	previous := make(map[int64]Task, len(before))
+	for _, t := range before {
+		previous[t.Id] = t
+	}
+
+	var events []Event
+	for _, t := range after {
+		old, existed := previous[t.Id]
+		delete(previous, t.Id)
+		if existed {
+			same, err := isSameJSON(old, t)
+			if err != nil {
+				return nil, err
+			}
+			if same {
+				continue
+			}
+		}
+		e := Event{Type: eventType, TaskID: t.Id, After: &t}
+		if existed {
+			e.Before = &old
+		}
+		events = append(events, e)
+	}
+	// Anything left over was removed
+	for _, t := range before {
+		if old, removed := previous[t.Id]; removed {
+			events = append(events, Event{Type: eventType, TaskID: t.Id, Before: &old})
+		}
+	}
+	return events, nil
+}
+
+func isSameJSON(a, b Task) (bool, error) {
+	aj, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bj, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return string(aj) == string(bj), nil
+}
+
+// applySnapshot replaces, inserts or removes the task with the given id
+func applySnapshot(tasks []Task, id int64, snapshot *Task) []Task {
+	for i := range tasks {
+		if tasks[i].Id != id {
+			continue
+		}
+		if snapshot == nil {
+			return append(tasks[:i], tasks[i+1:]...)
+		}
+		tasks[i] = snapshot.clone()
+		return tasks
+	}
+	if snapshot == nil {
+		return tasks
+	}
+	// Keep the list ordered by id when bringing a task back
+	for i := range tasks {
+		if tasks[i].Id > id {
+			return append(tasks[:i], append([]Task{snapshot.clone()}, tasks[i:]...)...)
+		}
+	}
+	return append(tasks, snapshot.clone())
+}
@@ -0,0 +1,334 @@
+package task
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUndoRedo(t *testing.T) {
+	testTime := time.Now()
+
+	// each step runs against the same service, expected is the description of
+	// every task left afterwards
+	type step struct {
+		action        func(svc Tasker) error
+		expected      []string
+		expectedError error
+	}
+	add := func(desc string) func(svc Tasker) error {
+		return func(svc Tasker) error {
+			_, err := svc.Add(Task{Description: desc})
+			return err
+		}
+	}
+	undo := func(svc Tasker) error {
+		_, err := svc.Undo()
+		return err
+	}
+	redo := func(svc Tasker) error {
+		_, err := svc.Redo()
+		return err
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "undoRestoresDeletedTask",
+			steps: []step{
+				{action: add("first"), expected: []string{"first"}},
+				{action: add("second"), expected: []string{"first", "second"}},
+				{action: func(svc Tasker) error { return svc.Delete(1) }, expected: []string{"second"}},
+				{action: undo, expected: []string{"first", "second"}},
+				{action: redo, expected: []string{"second"}},
+			},
+		},
+		{
+			name: "undoWalksBackThroughUpdates",
+			steps: []step{
+				{action: add("first"), expected: []string{"first"}},
+				{action: func(svc Tasker) error { return svc.Update(1, Task{Description: "changed"}) }, expected: []string{"changed"}},
+				{action: undo, expected: []string{"first"}},
+				{action: undo, expected: []string{}},
+				{action: undo, expected: []string{}, expectedError: ErrNothingToUndo},
+				{action: redo, expected: []string{"first"}},
+			},
+		},
+		{
+			name: "newMutationClearsRedo",
+			steps: []step{
+				{action: add("first"), expected: []string{"first"}},
+				{action: undo, expected: []string{}},
+				{action: add("second"), expected: []string{"second"}},
+				{action: redo, expected: []string{"second"}, expectedError: ErrNothingToRedo},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			fileName := "test-" + tst.name + ".json"
+
+			// Cleanup files when done
+			t.Cleanup(func() {
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
+				}
+			})
+
+			svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(func() time.Time { return testTime }))
+			for i, st := range tst.steps {
+				if err := st.action(svc); !errors.Is(err, st.expectedError) {
+					t.Fatalf("%s step %d expected error %v but got %v", tst.name, i, st.expectedError, err)
+				}
+				tasks, err := svc.List(ListOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				var actual []string
+				for _, task := range tasks {
+					actual = append(actual, task.Description)
+				}
+				if len(actual) != len(st.expected) {
+					t.Fatalf("%s step %d expected %v but got %v", tst.name, i, st.expected, actual)
+				}
+				for j := range actual {
+					if actual[j] != st.expected[j] {
+						t.Fatalf("%s step %d expected %v but got %v", tst.name, i, st.expected, actual)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestJournalRecordsEvents(t *testing.T) {
+	testTime := time.Now()
+	journal := NewMemoryJournal()
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithJournal(journal), WithTimeFunction(func() time.Time { return testTime }))
+
+	if _, err := svc.Add(Task{Description: "Test the journal"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(1, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := journal.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events but got %v", events)
+	}
+	if events[0].Type != EventAdd || events[0].Before != nil || events[0].After == nil || events[0].Tx != 1 {
+		t.Errorf("expected an add event with no before snapshot but got %+v", events[0])
+	}
+	if events[1].Type != EventMark || events[1].Before.Status != StatusTodo || events[1].After.Status != StatusDone || events[1].Tx != 2 {
+		t.Errorf("expected a mark event from todo to done but got %+v", events[1])
+	}
+	if !events[1].At.Equal(testTime) {
+		t.Errorf("expected event time %v but got %v", testTime, events[1].At)
+	}
+}
+
+func TestFileJournalTornLine(t *testing.T) {
+	fileName := "test-TestFileJournalTornLine.journal"
+	t.Cleanup(func() { deleteFile(fileName) })
+
+	journal := NewFileJournal(fileName)
+	if err := journal.Append(Event{Tx: 1, Type: EventAdd, TaskID: 1}, Event{Tx: 2, Type: EventAdd, TaskID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash part way through an append leaves half a line behind
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"tx":3,"type":"ad`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	events, err := journal.Events()
+	if err != nil || len(events) != 2 {
+		t.Fatalf("expected the 2 complete events but got %v, %v", events, err)
+	}
+	if err := journal.Append(Event{Tx: 3, Type: EventAdd, TaskID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	events, err = journal.Events()
+	if err != nil || len(events) != 3 || events[2].TaskID != 3 {
+		t.Fatalf("expected the torn line replaced by the new event but got %v, %v", events, err)
+	}
+
+	// Damage before the last line is not a torn write
+	if err := os.WriteFile(fileName, []byte("{\"tx\":1}\nnot json\n{\"tx\":2}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journal.Events(); !errors.Is(err, ErrCorruptJournal) {
+		t.Errorf("expected %v but got %v", ErrCorruptJournal, err)
+	}
+}
+
+// failingStore refuses to save, like a full disk
+type failingStore struct {
+	Store
+}
+
+func (s failingStore) Save([]Task) error {
+	return errors.New("disk full")
+}
+
+func TestJournalMatchesStore(t *testing.T) {
+	now := func() time.Time { return timeMustParse(time.RFC3339, "2026-01-14T12:00:00Z") }
+
+	// A failed save takes its events back out of the journal
+	journal := NewMemoryJournal()
+	svc := NewTaskService(WithStore(failingStore{NewMemoryStore()}), WithJournal(journal), WithTimeFunction(now))
+	if _, err := svc.Add(Task{Description: "Never saved"}); err == nil {
+		t.Fatal("expected the save to fail")
+	}
+	if events, _ := journal.Events(); len(events) != 0 {
+		t.Errorf("expected an empty journal but got %v", events)
+	}
+
+	// Events journaled just before a crash are dropped on the next write
+	journal = NewMemoryJournal()
+	svc = NewTaskService(WithStore(NewMemoryStore()), WithJournal(journal), WithTimeFunction(now))
+	if _, err := svc.Add(Task{Description: "Saved"}); err != nil {
+		t.Fatal(err)
+	}
+	phantom := Task{Id: 2, Description: "Crashed"}
+	if err := journal.Append(Event{Tx: 2, Type: EventAdd, TaskID: 2, After: &phantom}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Update(1, Task{Description: "Saved twice"}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := journal.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[1].Type != EventUpdate || events[1].Tx != 2 {
+		t.Errorf("expected the phantom add replaced by the update but got %v", events)
+	}
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := svc.Get(1); got.Description != "Saved" {
+		t.Errorf("expected undo to revert the update but got %q", got.Description)
+	}
+}
+
+func TestCompactEvents(t *testing.T) {
+	journal := NewMemoryJournal()
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithJournal(journal), WithTimeFunction(time.Now))
+	for _, desc := range []string{"one", "two", "three", "four", "five"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Delete(5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Purge(0); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := svc.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, _ := journal.Events()
+	compacted := compactEvents(events, 2)
+	if len(compacted) >= len(events) {
+		t.Fatalf("expected fewer events after compacting but got %d from %d", len(compacted), len(events))
+	}
+	done, undone := replayStacks(events)
+	compactedDone, compactedUndone := replayStacks(compacted)
+	if !slices.Equal(compactedDone, done[len(done)-2:]) || !slices.Equal(compactedUndone, undone) {
+		t.Errorf("expected stacks %v %v but got %v %v", done[len(done)-2:], undone, compactedDone, compactedUndone)
+	}
+	if err := journal.(Rewriter).Rewrite(compacted); err != nil {
+		t.Fatal(err)
+	}
+
+	// Redo still works, ids are not reused and undo stops at the kept depth:
+	// the 2 kept adds, the 2 redone changes and the new add
+	for range 2 {
+		if _, err := svc.Redo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	id, err := svc.Add(Task{Description: "six"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 6 {
+		t.Errorf("expected id 6 but got %d", id)
+	}
+	for range 5 {
+		if _, err := svc.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("expected %v but got %v", ErrNothingToUndo, err)
+	}
+}
+
+func TestJournalKeepsHandEdits(t *testing.T) {
+	fileName := "test-TestJournalKeepsHandEdits.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+
+	svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now))
+	for _, desc := range []string{"one", "two"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileName, bytes.Replace(data, []byte(`"two"`), []byte(`"two edited"`), 1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The add of "two" matches neither side of the file, so it stays
+	if _, err := svc.Add(Task{Description: "three"}); err != nil {
+		t.Fatal(err)
+	}
+	if warnings := svc.Warnings(); !slices.ContainsFunc(warnings, func(err error) bool { return errors.Is(err, ErrJournalDiverged) }) {
+		t.Errorf("expected a warning about the hand edit but got %v", warnings)
+	}
+	for range 2 {
+		if _, err := svc.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 || tasks[0].Description != "one" {
+		t.Errorf("expected only task one left but got %v", tasks)
+	}
+}
@@ -0,0 +1,163 @@
+package task
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// ListOptions narrows down which tasks List returns. Nil fields match
+// everything
+type ListOptions struct {
+	Status   *Status
+	Priority *Priority
+	View     View
+	// Tags must all be present and ExcludeTags must all be absent
+	Tags        []string
+	ExcludeTags []string
+	// ProjectID limits the list to one project, zero means tasks without one
+	ProjectID *int64
+	// Fields filters on custom fields declared in the schema
+	Fields []FieldFilter
+	// Filter is a compiled filter such as a parsed query, checked on top of
+	// everything else
+	Filter Matcher
+	// Sort replaces the priority order, priority still breaks ties
+	Sort []SortKey
+	// Limit caps how many tasks are returned, zero means no limit. Offset
+	// skips tasks and After skips every task up to and including the one with
+	// that id, so the last id of a page is the cursor for the next
+	Limit  int
+	Offset int
+	After  int64
+}
+
+// Matcher decides whether a task belongs in a list
+type Matcher interface {
+	Match(t Task, env Env) bool
+}
+
+// Env is everything a filter may need to know besides the task itself
+type Env struct {
+	Now time.Time
+	// Tasks holds every task by id, including trashed ones
+	Tasks map[int64]Task
+}
+
+func newEnv(now time.Time, tasks []Task) Env {
+	return Env{Now: now, Tasks: tasksByID(tasks)}
+}
+
+// View is a named slice of the task list such as overdue or blocked tasks,
+// always relative to the service's NowFunc
+type View string
+
+// Match reports whether the task falls in the view, the empty view matches
+// everything and unknown views nothing
+func (v View) Match(t Task, env Env) bool {
+	switch v {
+	case "":
+		return true
+	case ViewOverdue, ViewToday, ViewUpcoming:
+		return matchDue(v, t, env.Now)
+	case ViewBlocked:
+		return t.Status != StatusDone && len(openBlockers(t, env.Tasks)) > 0
+	case ViewReady:
+		return t.Status != StatusDone && len(openBlockers(t, env.Tasks)) == 0
+	}
+	return false
+}
+
+// Views lists every view View.Match knows
+func Views() []View {
+	return []View{ViewOverdue, ViewToday, ViewUpcoming, ViewBlocked, ViewReady}
+}
+
+func tasksByID(tasks []Task) map[int64]Task {
+	byID := make(map[int64]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.Id] = t
+	}
+	return byID
+}
+
+func (o ListOptions) match(t Task, env Env) bool {
+	if o.Status != nil && t.Status != *o.Status {
+		return false
+	}
+	if o.Priority != nil && t.Priority != *o.Priority {
+		return false
+	}
+	if o.ProjectID != nil && t.ProjectID != *o.ProjectID {
+		return false
+	}
+	for _, tag := range o.Tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range o.ExcludeTags {
+		if t.HasTag(tag) {
+			return false
+		}
+	}
+	if o.Filter != nil && !o.Filter.Match(t, env) {
+		return false
+	}
+	return o.View.Match(t, env)
+}
+
+// List returns every task that is not in the trash and matches opts, highest
+// priority first and otherwise in the order they were added. Unknown fields in
+// opts are ErrUnknownField and a cursor naming a task that doesn't exist is
+// ErrInvalidCursor
+func (s TaskService) List(opts ListOptions) ([]Task, error) {
+	now := s.now()
+	fields := make([]Matcher, len(opts.Fields))
+	for i, f := range opts.Fields {
+		m, err := s.schema.Filter(f, now)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = m
+	}
+	sortFunc, err := s.sortFunc(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	// filter out trashed tasks and anything not matching the options
+	env := newEnv(now, tasks)
+	filteredList := []Task{}
+	for _, task := range tasks {
+		if task.Trashed() || !opts.match(task, env) {
+			continue
+		}
+		if slices.ContainsFunc(fields, func(m Matcher) bool { return !m.Match(task, env) }) {
+			continue
+		}
+		filteredList = append(filteredList, task)
+	}
+
+	// Ties fall back to priority and then to the order tasks were added in,
+	// which makes the order total so a cursor can be searched for
+	position := make(map[int64]int, len(tasks))
+	for i, t := range tasks {
+		position[t.Id] = i
+	}
+	order := func(a, b Task) int {
+		return cmp.Or(sortFunc(a, b), cmp.Compare(b.Priority, a.Priority), cmp.Compare(position[a.Id], position[b.Id]))
+	}
+	slices.SortFunc(filteredList, order)
+	return opts.page(filteredList, tasks, order)
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package task
+
+import "time"
+
+// lockFile is a no-op where flock is unavailable, concurrent invocations are
+// not protected on these platforms
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,75 @@
+//go:build unix
+
+package task
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockTimesOut(t *testing.T) {
+	fileName := "test-TestLockTimesOut.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+
+	// Hold the lock as if another process were mid-write
+	unlock, err := lockFile(lockPath(fileName), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now), WithLockTimeout(50*time.Millisecond))
+	if _, err := svc.Add(Task{Description: "Test the lock times out"}); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("expected %v but got %v", ErrLockTimeout, err)
+	}
+}
+
+func TestConcurrentAddKeepsEveryTask(t *testing.T) {
+	fileName := "test-TestConcurrentAddKeepsEveryTask.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+
+	const workers = 10
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate services mimic separate task-cli processes
+			svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now))
+			if _, err := svc.Add(Task{Description: "Test concurrent adds"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	tasks, err := load(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != workers {
+		t.Fatalf("expected %d tasks but got %d", workers, len(tasks))
+	}
+	seen := map[int64]bool{}
+	for _, task := range tasks {
+		if seen[task.Id] {
+			t.Errorf("duplicate task id %d", task.Id)
+		}
+		seen[task.Id] = true
+	}
+}
@@ -0,0 +1,35 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrEmptyNote = errors.New("note is empty")
+
+// Note is a timestamped comment kept alongside a task's description. Text may
+// span several lines
+type Note struct {
+	At   time.Time `json:"at"`
+	Text string    `json:"text"`
+}
+
+// AddNote appends a note to a task, surrounding blank space is trimmed
+func (s TaskService) AddNote(id int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("%w for task %d", ErrEmptyNote, id)
+	}
+	return s.mutate(EventNote, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		now := s.now()
+		tasks[i].Notes = append(tasks[i].Notes, Note{At: now, Text: text})
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAddNote(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	id, err := svc.Add(Task{Description: "Fix login bug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddNote(id, "  Happens on Safari only\n"); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := svc.AddNote(id, "Steps:\n1. open login\n2. submit"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AddNote(id, " \n\t"); !errors.Is(err, ErrEmptyNote) {
+		t.Errorf("expected %v but got %v", ErrEmptyNote, err)
+	}
+	if err := svc.AddNote(99, "Missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v but got %v", ErrNotFound, err)
+	}
+
+	// Updating the description leaves the notes alone
+	if err := svc.Update(id, Task{Description: "Fix Safari login bug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := tasks[0].Notes
+	if len(notes) != 2 || notes[0].Text != "Happens on Safari only" || notes[1].Text != "Steps:\n1. open login\n2. submit" {
+		t.Fatalf("expected two trimmed notes but got %+v", notes)
+	}
+	if !notes[1].At.Equal(now) {
+		t.Errorf("expected the second note at %v but got %v", now, notes[1].At)
+	}
+
+	// Notes are journaled like any other change
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks[0].Notes) != 1 {
+		t.Errorf("expected undo to drop the second note but got %+v", tasks[0].Notes)
+	}
+}
@@ -0,0 +1,63 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Priority orders tasks by importance. The zero value is medium so task files
+// written before priorities existed load with a sensible default
+type Priority int
+
+const (
+	PriorityLow Priority = iota - 1
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+var ErrInvalidPriority = errors.New("invalid priority")
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	case PriorityUrgent:
+		return "urgent"
+	}
+	return ""
+}
+
+func ParsePriority(s string) (Priority, error) {
+	for p := PriorityLow; p <= PriorityUrgent; p++ {
+		if strings.EqualFold(s, p.String()) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q, expected low, medium, high or urgent", ErrInvalidPriority, s)
+}
+
+func (s TaskService) Prioritize(id int64, priority Priority) error {
+	if priority.String() == "" {
+		return fmt.Errorf("%w %d", ErrInvalidPriority, priority)
+	}
+	return s.mutate(EventPrioritize, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if tasks[i].Priority == priority {
+			return tasks, nil
+		}
+		now := s.now()
+		tasks[i].record(now, FieldPriority, tasks[i].Priority.String(), priority.String())
+		tasks[i].Priority = priority
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+		falsy    bool
+	}{
+		{input: "low", expected: PriorityLow},
+		{input: "Medium", expected: PriorityMedium},
+		{input: "HIGH", expected: PriorityHigh},
+		{input: "urgent", expected: PriorityUrgent},
+		{input: "whenever", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := ParsePriority(tst.input)
+		if tst.falsy {
+			if !errors.Is(err, ErrInvalidPriority) {
+				t.Errorf("%s expected %v but got %v", tst.input, ErrInvalidPriority, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		if actual != tst.expected {
+			t.Errorf("%s expected %v but got %v", tst.input, tst.expected, actual)
+		}
+	}
+}
+
+func TestListByPriority(t *testing.T) {
+	fileName := "test-TestListByPriority.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+
+	// Task 1 was saved before priorities existed and should load as medium
+	content := `[{"id":1,"description":"old task","status":0,"createdAt":"2025-12-12T13:13:59Z"}]`
+	if err := os.WriteFile(fileName, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now))
+	for _, task := range []Task{
+		{Description: "low", Priority: PriorityLow},
+		{Description: "urgent", Priority: PriorityUrgent},
+		{Description: "other medium"},
+		{Description: "soon to be high"},
+	} {
+		if _, err := svc.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Prioritize(5, PriorityHigh); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func() ([]Task, error) { return svc.List(ListOptions{}) }
+	assertIDs(t, "sorted", list, []int64{3, 5, 1, 4, 2})
+
+	medium := PriorityMedium
+	filtered := func() ([]Task, error) { return svc.List(ListOptions{Priority: &medium}) }
+	assertIDs(t, "filtered", filtered, []int64{1, 4})
+}
@@ -0,0 +1,230 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type ProjectStatus int
+
+const (
+	ProjectActive ProjectStatus = iota
+	ProjectArchived
+)
+
+func (s ProjectStatus) String() string {
+	switch s {
+	case ProjectActive:
+		return "active"
+	case ProjectArchived:
+		return "archived"
+	}
+	return ""
+}
+
+var (
+	ErrProjectNotFound = errors.New("project not found")
+	ErrProjectExists   = errors.New("project already exists")
+	ErrProjectArchived = errors.New("project is archived")
+	ErrInvalidProject  = errors.New("invalid project")
+	ErrNoProjectStore  = errors.New("store does not keep projects")
+)
+
+// Project groups tasks, a task belongs to at most one project
+type Project struct {
+	Id          int64         `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Status      ProjectStatus `json:"status"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// AddProject creates a project and returns its id. Names are unique ignoring
+// case
+func (s TaskService) AddProject(p Project) (int64, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" || strings.ContainsAny(p.Name, " \t") {
+		return 0, fmt.Errorf("%w name %q, names can't be blank or contain spaces", ErrInvalidProject, p.Name)
+	}
+	// "none" always means no project when filtering or assigning
+	if strings.EqualFold(p.Name, "none") {
+		return 0, fmt.Errorf("%w name %q, it means no project", ErrInvalidProject, p.Name)
+	}
+
+	err := s.mutateProjects(func(projects []Project) ([]Project, error) {
+		var maxID int64 = 0
+		for _, existing := range projects {
+			if strings.EqualFold(existing.Name, p.Name) {
+				return nil, fmt.Errorf("%w with name %s", ErrProjectExists, p.Name)
+			}
+			maxID = max(maxID, existing.Id)
+		}
+		p.Id = maxID + 1
+		p.Status = ProjectActive
+		p.CreatedAt = s.now()
+		return append(projects, p), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return p.Id, nil
+}
+
+func (s TaskService) Projects() ([]Project, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return s.loadProjects()
+}
+
+// loadProjects reads the projects without taking the lock, a store that
+// can't keep projects has none
+func (s TaskService) loadProjects() ([]Project, error) {
+	store, ok := s.store.(ProjectStore)
+	if !ok {
+		return []Project{}, nil
+	}
+	return store.LoadProjects()
+}
+
+// FindProject looks a project up by name, ignoring case
+func (s TaskService) FindProject(name string) (Project, error) {
+	projects, err := s.Projects()
+	if err != nil {
+		return Project{}, err
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Project{}, fmt.Errorf("%w with name %s", ErrProjectNotFound, name)
+}
+
+// ArchiveProject closes a project to new tasks, its existing tasks are kept
+func (s TaskService) ArchiveProject(name string) error {
+	return s.mutateProjects(func(projects []Project) ([]Project, error) {
+		for i := range projects {
+			if strings.EqualFold(projects[i].Name, name) {
+				projects[i].Status = ProjectArchived
+				return projects, nil
+			}
+		}
+		return nil, fmt.Errorf("%w with name %s", ErrProjectNotFound, name)
+	})
+}
+
+func (s TaskService) mutateProjects(fn func(projects []Project) ([]Project, error)) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	store, ok := s.store.(ProjectStore)
+	if !ok {
+		return ErrNoProjectStore
+	}
+	projects, err := store.LoadProjects()
+	if err != nil {
+		return err
+	}
+	projects, err = fn(append([]Project{}, projects...))
+	if err != nil {
+		return err
+	}
+	return store.SaveProjects(projects)
+}
+
+// AssignProject moves a task into a project, zero takes it out of any
+// project. Subtasks that were in the same project as the task move with it
+func (s TaskService) AssignProject(id, projectID int64) error {
+	return s.mutate(EventAssign, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.checkProject(projectID); err != nil {
+			return nil, err
+		}
+		projects, err := s.loadProjects()
+		if err != nil {
+			return nil, err
+		}
+		name := func(id int64) string {
+			for _, p := range projects {
+				if p.Id == id {
+					return p.Name
+				}
+			}
+			return ""
+		}
+
+		now := s.now()
+		from := tasks[i].ProjectID
+		for _, j := range append([]int{i}, descendants(tasks, id)...) {
+			if tasks[j].ProjectID != from || from == projectID {
+				continue
+			}
+			tasks[j].record(now, FieldProject, name(from), name(projectID))
+			tasks[j].ProjectID = projectID
+			tasks[j].UpdatedAt = now
+		}
+		return tasks, nil
+	})
+}
+
+// checkProject makes sure tasks only go into active projects
+func (s TaskService) checkProject(id int64) error {
+	if id == 0 {
+		return nil
+	}
+	projects, err := s.loadProjects()
+	if err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if p.Id != id {
+			continue
+		}
+		if p.Status == ProjectArchived {
+			return fmt.Errorf("%w: %s", ErrProjectArchived, p.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w with id %d", ErrProjectNotFound, id)
+}
+
+func saveProjects(path string, projects []Project) error {
+	js, err := json.Marshal(projects)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, js)
+}
+
+func loadProjects(path string) ([]Project, error) {
+	bytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Project{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var projects []Project
+	if err := json.Unmarshal(bytes, &projects); err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func projectsPath(savePath string) string {
+	return savePath + ".projects"
+}
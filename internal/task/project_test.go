@@ -0,0 +1,144 @@
+package task
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestProjects(t *testing.T) {
+	fileName := "test-TestProjects.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+	svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now))
+
+	webID, err := svc.AddProject(Project{Name: "web", Description: "The website"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.AddProject(Project{Name: "WEB"}); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("expected %v but got %v", ErrProjectExists, err)
+	}
+	for _, name := range []string{"two words", "None"} {
+		if _, err := svc.AddProject(Project{Name: name}); !errors.Is(err, ErrInvalidProject) {
+			t.Errorf("%q expected %v but got %v", name, ErrInvalidProject, err)
+		}
+	}
+	opsID, err := svc.AddProject(Project{Name: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range []Task{
+		{Description: "Build landing page", ProjectID: webID},
+		{Description: "Rotate keys", ProjectID: opsID},
+		{Description: "No project"},
+	} {
+		if _, err := svc.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.Add(Task{Description: "Missing project", ProjectID: 42}); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("expected %v but got %v", ErrProjectNotFound, err)
+	}
+
+	if err := svc.ArchiveProject("ops"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Add(Task{Description: "Archived project", ProjectID: opsID}); !errors.Is(err, ErrProjectArchived) {
+		t.Errorf("expected %v but got %v", ErrProjectArchived, err)
+	}
+	ops, err := svc.FindProject("Ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ops.Status != ProjectArchived {
+		t.Errorf("expected project to be archived but got %s", ops.Status)
+	}
+
+	// Projects survive a fresh service reading the same files
+	projects, err := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now)).Projects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[0].Name != "web" || projects[0].Description != "The website" {
+		t.Errorf("expected web and ops projects but got %v", projects)
+	}
+
+	var noProject int64 = 0
+	inWeb := func() ([]Task, error) { return svc.List(ListOptions{ProjectID: &webID}) }
+	withoutProject := func() ([]Task, error) { return svc.List(ListOptions{ProjectID: &noProject}) }
+	assertIDs(t, "web", inWeb, []int64{1})
+	assertIDs(t, "no project", withoutProject, []int64{3})
+}
+
+func TestAssignProject(t *testing.T) {
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(time.Now))
+	webID, err := svc.AddProject(Project{Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opsID, err := svc.AddProject(Project{Name: "ops"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range []Task{
+		{Description: "Launch", ProjectID: webID},
+		{Description: "Write copy", ParentID: 1},
+		{Description: "Order servers", ParentID: 1, ProjectID: opsID},
+	} {
+		if _, err := svc.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Subtasks in the same project move along, others stay put
+	if err := svc.AssignProject(1, opsID); err != nil {
+		t.Fatal(err)
+	}
+	inOps := func() ([]Task, error) { return svc.List(ListOptions{ProjectID: &opsID}) }
+	assertIDs(t, "ops", inOps, []int64{1, 2, 3})
+	history, err := svc.History(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.Field != FieldProject || last.From != "web" || last.To != "ops" {
+		t.Errorf("expected a project change from web to ops but got %+v", last)
+	}
+
+	if err := svc.AssignProject(3, 0); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "ops", inOps, []int64{1, 2})
+	if err := svc.ArchiveProject("web"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.AssignProject(1, webID); !errors.Is(err, ErrProjectArchived) {
+		t.Errorf("expected %v but got %v", ErrProjectArchived, err)
+	}
+
+	// A store without project support still lists tasks but can't add projects
+	bare := NewTaskService(WithStore(taskOnlyStore{NewMemoryStore()}), WithTimeFunction(time.Now))
+	if projects, err := bare.Projects(); err != nil || len(projects) != 0 {
+		t.Errorf("expected no projects but got %v, %v", projects, err)
+	}
+	if _, err := bare.AddProject(Project{Name: "web"}); !errors.Is(err, ErrNoProjectStore) {
+		t.Errorf("expected %v but got %v", ErrNoProjectStore, err)
+	}
+}
+
+// taskOnlyStore hides the project methods of the store it wraps
+type taskOnlyStore struct {
+	store Store
+}
+
+func (s taskOnlyStore) Load() ([]Task, error)      { return s.store.Load() }
+func (s taskOnlyStore) Save(tasks []Task) error    { return s.store.Save(tasks) }
+func (s taskOnlyStore) Get(id int64) (Task, error) { return s.store.Get(id) }
+func (s taskOnlyStore) Put(t Task) error           { return s.store.Put(t) }
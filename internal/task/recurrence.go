@@ -0,0 +1,230 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ColinEge/task-cli/internal/dateparse"
+)
+
+type RecurrenceKind string
+
+const (
+	RecurDaily    RecurrenceKind = "daily"
+	RecurWeekly   RecurrenceKind = "weekly"
+	RecurMonthly  RecurrenceKind = "monthly"
+	RecurInterval RecurrenceKind = "interval"
+)
+
+var ErrInvalidRecurrence = errors.New("invalid recurrence")
+
+// Recurrence describes when a repeating task comes around again. Weekly rules
+// without weekdays repeat on the same weekday as the previous occurrence.
+// Monthly rules without a day are given one when set on a task, see anchor
+type Recurrence struct {
+	Kind RecurrenceKind `json:"kind"`
+	// Every is the number of days between interval occurrences
+	Every    int            `json:"every,omitzero"`
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	// Day is the day of the month for monthly rules, clamped to short months
+	Day int `json:"day,omitzero"`
+}
+
+// ParseRecurrence reads rules such as "daily", "weekly mon,thu", "monthly 15"
+// or "every 3 days"
+func ParseRecurrence(s string) (Recurrence, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	invalid := fmt.Errorf(`%w %q, expected daily, weekly [mon,thu], monthly [day] or every <n> days`, ErrInvalidRecurrence, s)
+	if len(fields) == 0 {
+		return Recurrence{}, invalid
+	}
+
+	switch fields[0] {
+	case "daily":
+		if len(fields) == 1 {
+			return Recurrence{Kind: RecurDaily}, nil
+		}
+	case "weekly":
+		r := Recurrence{Kind: RecurWeekly}
+		if len(fields) == 1 {
+			return r, nil
+		}
+		if len(fields) != 2 {
+			break
+		}
+		for _, name := range strings.Split(fields[1], ",") {
+			day, ok := dateparse.ParseWeekday(name)
+			if !ok {
+				return Recurrence{}, invalid
+			}
+			if !slices.Contains(r.Weekdays, day) {
+				r.Weekdays = append(r.Weekdays, day)
+			}
+		}
+		slices.Sort(r.Weekdays)
+		return r, nil
+	case "monthly":
+		if len(fields) == 1 {
+			return Recurrence{Kind: RecurMonthly}, nil
+		}
+		day, err := strconv.Atoi(fields[1])
+		if len(fields) == 2 && err == nil && day >= 1 && day <= 31 {
+			return Recurrence{Kind: RecurMonthly, Day: day}, nil
+		}
+	case "every":
+		if len(fields) != 3 {
+			break
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			break
+		}
+		switch strings.TrimSuffix(fields[2], "s") {
+		case "day":
+			return Recurrence{Kind: RecurInterval, Every: n}, nil
+		case "week":
+			return Recurrence{Kind: RecurInterval, Every: 7 * n}, nil
+		}
+	}
+	return Recurrence{}, invalid
+}
+
+func (r Recurrence) String() string {
+	switch r.Kind {
+	case RecurDaily:
+		return "daily"
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return "weekly"
+		}
+		names := make([]string, len(r.Weekdays))
+		for i, d := range r.Weekdays {
+			names[i] = strings.ToLower(d.String()[:3])
+		}
+		return "weekly " + strings.Join(names, ",")
+	case RecurMonthly:
+		if r.Day == 0 {
+			return "monthly"
+		}
+		return fmt.Sprintf("monthly %d", r.Day)
+	case RecurInterval:
+		return fmt.Sprintf("every %d days", r.Every)
+	}
+	return ""
+}
+
+// Next is the first occurrence after from, keeping from's time of day
+func (r Recurrence) Next(from time.Time) time.Time {
+	switch r.Kind {
+	case RecurWeekly:
+		if len(r.Weekdays) == 0 {
+			return from.AddDate(0, 0, 7)
+		}
+		for days := 1; days <= 7; days++ {
+			next := from.AddDate(0, 0, days)
+			if slices.Contains(r.Weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	case RecurMonthly:
+		day := r.Day
+		if day == 0 {
+			day = from.Day()
+		}
+		if next := onDayOfMonth(from, 0, day); next.After(from) {
+			return next
+		}
+		return onDayOfMonth(from, 1, day)
+	case RecurInterval:
+		return from.AddDate(0, 0, max(r.Every, 1))
+	}
+	return from.AddDate(0, 0, 1)
+}
+
+// onDayOfMonth moves months ahead and lands on day, or the last day of shorter
+// months
+func onDayOfMonth(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// SetRecurrence makes a task repeat, nil stops it repeating
+func (s TaskService) SetRecurrence(id int64, r *Recurrence) error {
+	if r != nil && r.String() == "" {
+		return fmt.Errorf("%w kind %q", ErrInvalidRecurrence, r.Kind)
+	}
+	return s.mutate(EventRecur, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		now := s.now()
+		rule := r.anchor(tasks[i].Due, now)
+		if tasks[i].Recurrence.describe() == rule.describe() {
+			return tasks, nil
+		}
+		tasks[i].record(now, FieldRecurrence, tasks[i].Recurrence.describe(), rule.describe())
+		tasks[i].Recurrence = rule
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
+
+// anchor pins a monthly rule without a day to the day of the due date, or of
+// now without one. Otherwise a clamp to a short month, 31st to the 28th,
+// would move every later occurrence to the 28th
+func (r *Recurrence) anchor(due, now time.Time) *Recurrence {
+	if r == nil || r.Kind != RecurMonthly || r.Day != 0 {
+		return r
+	}
+	from := due
+	if from.IsZero() {
+		from = now
+	}
+	anchored := *r
+	anchored.Day = from.Day()
+	return &anchored
+}
+
+func (r *Recurrence) describe() string {
+	if r == nil {
+		return ""
+	}
+	return r.String()
+}
+
+// nextOccurrence copies a finished recurring task into a fresh todo task due
+// at the next occurrence that is not already in the past
+func nextOccurrence(t Task, id int64, now time.Time) Task {
+	from := t.Due
+	if from.IsZero() {
+		from = now
+	}
+	due := t.Recurrence.Next(from)
+	for due.Before(startOfDay(now)) {
+		due = t.Recurrence.Next(due)
+	}
+
+	r := *t.Recurrence
+	r.Weekdays = slices.Clone(r.Weekdays)
+	next := Task{
+		Id:          id,
+		Description: t.Description,
+		Status:      StatusTodo,
+		Priority:    t.Priority,
+		Tags:        slices.Clone(t.Tags),
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Recurrence:  &r,
+		Estimate:    t.Estimate,
+		CreatedAt:   now,
+		Due:         due,
+	}
+	next.record(now, FieldStatus, "", StatusTodo.String())
+	return next
+}
@@ -0,0 +1,175 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		falsy    bool
+	}{
+		{input: "daily", expected: "daily"},
+		{input: "Weekly", expected: "weekly"},
+		{input: "weekly thu,mon,monday", expected: "weekly mon,thu"},
+		{input: "monthly", expected: "monthly"},
+		{input: "monthly 31", expected: "monthly 31"},
+		{input: "every 3 days", expected: "every 3 days"},
+		{input: "every 2 weeks", expected: "every 14 days"},
+		{input: "weekly someday", falsy: true},
+		{input: "monthly 32", falsy: true},
+		{input: "every 0 days", falsy: true},
+		{input: "hourly", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := ParseRecurrence(tst.input)
+		if tst.falsy {
+			if !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("%q expected %v but got %v", tst.input, ErrInvalidRecurrence, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if actual.String() != tst.expected {
+			t.Errorf("%q expected %s but got %s", tst.input, tst.expected, actual.String())
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	// A Wednesday morning
+	from := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+
+	tests := []struct {
+		rule     string
+		expected string
+	}{
+		{rule: "daily", expected: "2026-01-15T09:00:00Z"},
+		{rule: "weekly", expected: "2026-01-21T09:00:00Z"},
+		{rule: "weekly mon,thu", expected: "2026-01-15T09:00:00Z"},
+		{rule: "weekly mon", expected: "2026-01-19T09:00:00Z"},
+		{rule: "monthly", expected: "2026-02-14T09:00:00Z"},
+		{rule: "monthly 20", expected: "2026-01-20T09:00:00Z"},
+		{rule: "monthly 10", expected: "2026-02-10T09:00:00Z"},
+		{rule: "every 3 days", expected: "2026-01-17T09:00:00Z"},
+	}
+	for _, tst := range tests {
+		r, err := ParseRecurrence(tst.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := r.Next(from)
+		if !actual.Equal(timeMustParse(time.RFC3339, tst.expected)) {
+			t.Errorf("%s expected %s but got %s", tst.rule, tst.expected, actual.Format(time.RFC3339))
+		}
+	}
+
+	// Monthly on the 31st lands on the last day of short months
+	r, _ := ParseRecurrence("monthly 31")
+	actual := r.Next(timeMustParse(time.RFC3339, "2026-01-31T09:00:00Z"))
+	if expected := timeMustParse(time.RFC3339, "2026-02-28T09:00:00Z"); !actual.Equal(expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestRecurringTaskSpawnsNextOccurrence(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T12:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	weekly, _ := ParseRecurrence("weekly mon,thu")
+	id, err := svc.Add(Task{
+		Description: "Weekly report +work",
+		Priority:    PriorityHigh,
+		Due:         timeMustParse(time.RFC3339, "2026-01-12T17:00:00Z"),
+		Recurrence:  &weekly,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	todo := StatusTodo
+	tasks, err := svc.List(ListOptions{Status: &todo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("expected the next occurrence to be added but got %v", tasks)
+	}
+	next := tasks[0]
+	expectedDue := timeMustParse(time.RFC3339, "2026-01-15T17:00:00Z")
+	if next.Id != 2 || !next.Due.Equal(expectedDue) || next.Priority != PriorityHigh || !next.HasTag("work") || next.Recurrence.String() != "weekly mon,thu" {
+		t.Errorf("expected task 2 due %v copying priority, tags and rule but got %+v", expectedDue, next)
+	}
+
+	// Undoing the mark removes the spawned occurrence too
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	all := func() ([]Task, error) { return svc.List(ListOptions{}) }
+	assertIDs(t, "after undo", all, []int64{1})
+
+	// Overdue occurrences skip ahead to today or later
+	if err := svc.SetRecurrence(id, &Recurrence{Kind: RecurDaily}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetDue(id, timeMustParse(time.RFC3339, "2026-01-01T17:00:00Z")); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = svc.List(ListOptions{Status: &todo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := timeMustParse(time.RFC3339, "2026-01-14T17:00:00Z"); len(tasks) != 1 || !tasks[0].Due.Equal(expected) {
+		t.Errorf("expected next occurrence due %v but got %v", expected, tasks)
+	}
+}
+
+func TestMonthlyRecurrenceKeepsItsDay(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-20T12:00:00Z")
+	journal := NewMemoryJournal()
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithJournal(journal), WithTimeFunction(func() time.Time { return now }))
+
+	monthly, _ := ParseRecurrence("monthly")
+	id, err := svc.Add(Task{Description: "Pay invoices", Due: timeMustParse(time.RFC3339, "2026-01-31T09:00:00Z"), Recurrence: &monthly})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The 31st clamps to February's last day and comes back in March
+	for _, expected := range []string{"2026-02-28T09:00:00Z", "2026-03-31T09:00:00Z"} {
+		if err := svc.Mark(id, StatusDone); err != nil {
+			t.Fatal(err)
+		}
+		id++
+		next, err := svc.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !next.Due.Equal(timeMustParse(time.RFC3339, expected)) || next.Recurrence.String() != "monthly 31" {
+			t.Errorf("expected monthly 31 due %s but got %s due %v", expected, next.Recurrence, next.Due)
+		}
+	}
+
+	// Setting the same rule again changes nothing
+	events, _ := journal.Events()
+	same, _ := ParseRecurrence("monthly 31")
+	if err := svc.SetRecurrence(id, &same); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := journal.Events(); len(after) != len(events) {
+		t.Errorf("expected no new events but got %d more", len(after)-len(events))
+	}
+}
@@ -0,0 +1,395 @@
+package task
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// indexVersion is bumped whenever tokenizing or weighting changes so older
+// index files are rebuilt rather than read
+const indexVersion = 2
+
+// searchGets is how many matches Search fetches one at a time, past that it
+// loads every task once since a file store reads the whole file for each Get
+const searchGets = 20
+
+var ErrEmptySearch = errors.New("nothing to search for")
+
+// SearchIndex is an inverted index over task descriptions and notes
+type SearchIndex struct {
+	Version int `json:"version"`
+	// Postings maps each term to the weight it carries in every task using it
+	Postings map[string]map[int64]float64 `json:"postings"`
+	// Docs lists the terms of each indexed task so it can be dropped without
+	// its old text
+	Docs map[int64][]string `json:"docs"`
+	// Terms lists every term in Postings sorted, so prefixes can be found by
+	// binary search
+	Terms []string `json:"terms"`
+	// Stamp is the store's Stamp when the index was last brought up to date
+	Stamp string `json:"stamp"`
+}
+
+// IndexStore persists the search index next to the tasks
+type IndexStore interface {
+	LoadIndex() (SearchIndex, error)
+	SaveIndex(index SearchIndex) error
+}
+
+type fileIndex struct {
+	path string
+}
+
+func NewFileIndex(path string) IndexStore {
+	return fileIndex{path: path}
+}
+
+// LoadIndex returns ErrFileNotExist when there is no usable index, including
+// one written by an older version
+func (f fileIndex) LoadIndex() (SearchIndex, error) {
+	bytes, err := os.ReadFile(f.path)
+	if err != nil {
+		return SearchIndex{}, err
+	}
+	var index SearchIndex
+	if err := json.Unmarshal(bytes, &index); err != nil || index.Version != indexVersion {
+		return SearchIndex{}, ErrFileNotExist
+	}
+	return index, nil
+}
+
+func (f fileIndex) SaveIndex(index SearchIndex) error {
+	js, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(f.path, js)
+}
+
+type memoryIndex struct {
+	mu    *sync.Mutex
+	index *SearchIndex
+}
+
+func NewMemoryIndex() IndexStore {
+	return memoryIndex{mu: &sync.Mutex{}, index: &SearchIndex{}}
+}
+
+func (m memoryIndex) LoadIndex() (SearchIndex, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.index.Version != indexVersion {
+		return SearchIndex{}, ErrFileNotExist
+	}
+	// Hand out a copy so a failed mutation can't leave half an update behind
+	js, err := json.Marshal(m.index)
+	if err != nil {
+		return SearchIndex{}, err
+	}
+	var index SearchIndex
+	return index, json.Unmarshal(js, &index)
+}
+
+func (m memoryIndex) SaveIndex(index SearchIndex) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*m.index = index
+	return nil
+}
+
+func indexPath(savePath string) string {
+	return savePath + ".index"
+}
+
+// WithIndex sets where the search index is kept
+func WithIndex(index IndexStore) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.index = index
+	}
+}
+
+// Tokenize splits text into lower case words, anything that isn't a letter or
+// digit separates words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func newSearchIndex(tasks []Task, stamp string) SearchIndex {
+	index := SearchIndex{Version: indexVersion, Postings: map[string]map[int64]float64{}, Docs: map[int64][]string{}, Stamp: stamp}
+	for _, t := range tasks {
+		index.add(t)
+	}
+	return index
+}
+
+// add indexes a task, words in the description count twice as much as words
+// in notes. Trashed tasks are left out
+func (idx *SearchIndex) add(t Task) {
+	if t.Trashed() {
+		return
+	}
+	weights := map[string]float64{}
+	for _, term := range Tokenize(t.Description) {
+		weights[term] += 2
+	}
+	for _, n := range t.Notes {
+		for _, term := range Tokenize(n.Text) {
+			weights[term]++
+		}
+	}
+	if len(weights) == 0 {
+		return
+	}
+	for term, w := range weights {
+		if idx.Postings[term] == nil {
+			idx.Postings[term] = map[int64]float64{}
+			i, _ := slices.BinarySearch(idx.Terms, term)
+			idx.Terms = slices.Insert(idx.Terms, i, term)
+		}
+		idx.Postings[term][t.Id] = w
+	}
+	idx.Docs[t.Id] = slices.Sorted(maps.Keys(weights))
+}
+
+func (idx *SearchIndex) remove(id int64) {
+	for _, term := range idx.Docs[id] {
+		delete(idx.Postings[term], id)
+		if len(idx.Postings[term]) == 0 {
+			delete(idx.Postings, term)
+			if i, found := slices.BinarySearch(idx.Terms, term); found {
+				idx.Terms = slices.Delete(idx.Terms, i, i+1)
+			}
+		}
+	}
+	delete(idx.Docs, id)
+}
+
+// SearchResult is a task matching every search term, Score ranks it against
+// the other results
+type SearchResult struct {
+	Task  Task
+	Score float64
+}
+
+// search scores every task containing all the terms, either whole or as the
+// start of a longer word. Rare words score higher than common ones and whole
+// words higher than prefixes
+func (idx SearchIndex) search(terms []string) map[int64]float64 {
+	total := float64(len(idx.Docs))
+	var scores map[int64]float64
+	for i, q := range terms {
+		best := map[int64]float64{}
+		start, _ := slices.BinarySearch(idx.Terms, q)
+		for _, term := range idx.Terms[start:] {
+			if !strings.HasPrefix(term, q) {
+				break
+			}
+			postings := idx.Postings[term]
+			idf := math.Log(1 + total/float64(len(postings)))
+			closeness := float64(len(q)) / float64(len(term))
+			for id, w := range postings {
+				best[id] = max(best[id], w*idf*closeness)
+			}
+		}
+		if i == 0 {
+			scores = best
+			continue
+		}
+		for id := range scores {
+			if s, ok := best[id]; ok {
+				scores[id] += s
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// Search finds tasks whose description or notes contain every word of query,
+// best match first. A limit of zero returns every match
+func (s TaskService) Search(query string, limit int) ([]SearchResult, error) {
+	terms := Tokenize(query)
+	slices.Sort(terms)
+	terms = slices.Compact(terms)
+	if len(terms) == 0 {
+		return nil, ErrEmptySearch
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	index, err := s.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+	scores := index.search(terms)
+	ids := slices.SortedFunc(maps.Keys(scores), func(a, b int64) int {
+		return cmp.Or(cmp.Compare(scores[b], scores[a]), cmp.Compare(a, b))
+	})
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+	tasks, err := s.tasksByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	for _, id := range ids {
+		if t, ok := tasks[id]; ok && !t.Trashed() {
+			results = append(results, SearchResult{Task: t, Score: scores[id]})
+		}
+	}
+	return results, nil
+}
+
+// tasksByIDs fetches the given tasks, leaving out any that no longer exist
+func (s TaskService) tasksByIDs(ids []int64) (map[int64]Task, error) {
+	if len(ids) > searchGets {
+		tasks, err := s.store.Load()
+		return tasksByID(tasks), err
+	}
+	tasks := make(map[int64]Task, len(ids))
+	for _, id := range ids {
+		t, err := s.store.Get(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks[id] = t
+	}
+	return tasks, nil
+}
+
+// searchIndex loads the index, rebuilding it from the tasks when it is
+// missing or the tasks changed without it, such as by editing the file by
+// hand. Failing to save the rebuilt index only means rebuilding it again
+func (s TaskService) searchIndex() (SearchIndex, error) {
+	stamp, err := s.stamp()
+	if err != nil {
+		return SearchIndex{}, err
+	}
+	index, err := s.index.LoadIndex()
+	if err == nil && index.Stamp == stamp {
+		return index, nil
+	}
+	if err != nil && !errors.Is(err, ErrFileNotExist) {
+		return SearchIndex{}, err
+	}
+
+	tasks, err := s.store.Load()
+	if err != nil && !errors.Is(err, ErrFileNotExist) {
+		return SearchIndex{}, err
+	}
+	// Loading may have created the file
+	if stamp, err = s.stamp(); err != nil {
+		return SearchIndex{}, err
+	}
+	index = newSearchIndex(tasks, stamp)
+	s.index.SaveIndex(index)
+	return index, nil
+}
+
+// Reindex rebuilds the search index from scratch
+func (s TaskService) Reindex() error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil && !errors.Is(err, ErrFileNotExist) {
+		return err
+	}
+	stamp, err := s.stamp()
+	if err != nil {
+		return err
+	}
+	return s.index.SaveIndex(newSearchIndex(tasks, stamp))
+}
+
+// stamp changes whenever the stored tasks do, stores that can't tell always
+// give the same stamp
+func (s TaskService) stamp() (string, error) {
+	if st, ok := s.store.(Stamper); ok {
+		return st.Stamp()
+	}
+	return "", nil
+}
+
+// updateIndex re-indexes the tasks with the given ids from their latest
+// state. before is the store's stamp from before the tasks were saved, an
+// index that wasn't up to date with it is rebuilt instead. The tasks are
+// already saved so failing here isn't an error, the index is emptied instead
+// and the next search rebuilds it
+func (s TaskService) updateIndex(tasks []Task, ids []int64, before string) {
+	if err := s.reindexTasks(tasks, ids, before); err != nil {
+		s.index.SaveIndex(SearchIndex{})
+	}
+}
+
+func (s TaskService) reindexTasks(tasks []Task, ids []int64, before string) error {
+	stamp, err := s.stamp()
+	if err != nil {
+		return err
+	}
+	index, err := s.index.LoadIndex()
+	if errors.Is(err, ErrFileNotExist) || (err == nil && index.Stamp != before) {
+		return s.index.SaveIndex(newSearchIndex(tasks, stamp))
+	}
+	if err != nil {
+		return err
+	}
+	byID := tasksByID(tasks)
+	for _, id := range ids {
+		index.remove(id)
+		if t, ok := byID[id]; ok {
+			index.add(t)
+		}
+	}
+	index.Stamp = stamp
+	return s.index.SaveIndex(index)
+}
+
+// Highlight wraps every word of text starting with one of the search terms in
+// before and after, leaving everything else as it was
+func Highlight(text string, terms []string, before, after string) string {
+	b := strings.Builder{}
+	word := strings.Builder{}
+	flush := func() {
+		w := word.String()
+		lower := strings.ToLower(w)
+		if w != "" && slices.ContainsFunc(terms, func(q string) bool { return strings.HasPrefix(lower, q) }) {
+			w = before + w + after
+		}
+		b.WriteString(w)
+		word.Reset()
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
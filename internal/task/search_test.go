@@ -0,0 +1,179 @@
+package task
+
+import (
+	"errors"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "Deploy the API", expected: []string{"deploy", "the", "api"}},
+		{input: "fix #42: log-in (Safari)", expected: []string{"fix", "42", "log", "in", "safari"}},
+		{input: "Ünïcode Straße", expected: []string{"ünïcode", "straße"}},
+		{input: " -- ", expected: nil},
+	}
+
+	for _, tst := range tests {
+		actual := Tokenize(tst.input)
+		if !slices.Equal(actual, tst.expected) {
+			t.Errorf("%q expected %v but got %v", tst.input, tst.expected, actual)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	actual := Highlight("Deploy the deployment, then DEP-12", []string{"dep"}, "[", "]")
+	expected := "[Deploy] the [deployment], then [DEP]-12"
+	if actual != expected {
+		t.Errorf("expected %q but got %q", expected, actual)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	for _, desc := range []string{"Deploy the API", "Write deployment notes", "Buy milk", "Review API docs"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.AddNote(3, "Remember to deploy the fridge"); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(query string) []int64 {
+		t.Helper()
+		results, err := svc.Search(query, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := []int64{}
+		for _, r := range results {
+			ids = append(ids, r.Task.Id)
+		}
+		return ids
+	}
+	tests := []struct {
+		query    string
+		expected []int64
+	}{
+		// Description matches outrank notes, whole words outrank prefixes
+		{query: "deploy", expected: []int64{1, 2, 3}},
+		{query: "DEP", expected: []int64{1, 2, 3}},
+		{query: "api", expected: []int64{1, 4}},
+		{query: "deploy api", expected: []int64{1}},
+		{query: "fridge", expected: []int64{3}},
+		{query: "rocket", expected: []int64{}},
+	}
+	for _, tst := range tests {
+		if actual := search(tst.query); !slices.Equal(actual, tst.expected) {
+			t.Errorf("%q expected %v but got %v", tst.query, tst.expected, actual)
+		}
+	}
+
+	// Mutations, undo and the trash keep the index up to date
+	if err := svc.Update(3, Task{Description: "Buy oat milk"}); err != nil {
+		t.Fatal(err)
+	}
+	if actual := search("oat"); !slices.Equal(actual, []int64{3}) {
+		t.Errorf("expected the update to be indexed but got %v", actual)
+	}
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if actual := search("oat"); len(actual) != 0 {
+		t.Errorf("expected the undo to be indexed but got %v", actual)
+	}
+	if err := svc.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	if actual := search("api"); !slices.Equal(actual, []int64{4}) {
+		t.Errorf("expected trashed tasks to be dropped but got %v", actual)
+	}
+	if err := svc.Restore(1); err != nil {
+		t.Fatal(err)
+	}
+	if actual := search("api"); !slices.Equal(actual, []int64{1, 4}) {
+		t.Errorf("expected restored tasks to come back but got %v", actual)
+	}
+}
+
+func TestSearchIndexFile(t *testing.T) {
+	fileName := "test-search-index.json"
+	t.Cleanup(func() {
+		for _, f := range testFiles(fileName) {
+			if err := deleteFile(f); err != nil {
+				log.Default().Print(err)
+			}
+		}
+	})
+
+	svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(time.Now))
+	if _, err := svc.Add(Task{Description: "Deploy the API"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(indexPath(fileName)); err != nil {
+		t.Fatalf("expected the index to be written next to the tasks: %v", err)
+	}
+
+	// A missing index is rebuilt from the tasks on the next search
+	if err := os.Remove(indexPath(fileName)); err != nil {
+		t.Fatal(err)
+	}
+	results, err := svc.Search("api", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Task.Id != 1 {
+		t.Errorf("expected task 1 but got %v", results)
+	}
+
+	// Editing the task file by hand is picked up without a reindex
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(data), "Deploy the API", "Deploy the website", 1)
+	if err := os.WriteFile(fileName, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err = svc.Search("website", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Task.Id != 1 {
+		t.Errorf("expected the edit to be indexed but got %v", results)
+	}
+}
+
+// failingIndex can't save, like an index file that isn't writable
+type failingIndex struct {
+	IndexStore
+}
+
+func (failingIndex) SaveIndex(SearchIndex) error {
+	return errors.New("permission denied")
+}
+
+func TestSearchIndexFailures(t *testing.T) {
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithIndex(failingIndex{NewMemoryIndex()}), WithTimeFunction(time.Now))
+	if _, err := svc.Add(Task{Description: "Deploy the API"}); err != nil {
+		t.Fatalf("expected the add to succeed without an index but got %v", err)
+	}
+	results, err := svc.Search("deploy", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Task.Id != 1 {
+		t.Errorf("expected task 1 but got %v", results)
+	}
+}
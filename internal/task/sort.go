@@ -0,0 +1,175 @@
+package task
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// SortKey orders the list by a task field or a custom field. Tasks without a
+// value, such as no due date, go last whichever the direction
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// builtinSorts compares the built in fields, ok is false when a task has no
+// value for the field
+var builtinSorts = map[string]func(t Task) (any, bool){
+	"id":          func(t Task) (any, bool) { return t.Id, true },
+	"description": func(t Task) (any, bool) { return strings.ToLower(t.Description), true },
+	"priority":    func(t Task) (any, bool) { return t.Priority, true },
+	"project":     func(t Task) (any, bool) { return t.ProjectID, t.ProjectID != 0 },
+	"parent":      func(t Task) (any, bool) { return t.ParentID, t.ParentID != 0 },
+	"estimate":    func(t Task) (any, bool) { return t.Estimate, t.Estimate != 0 },
+	"due":         func(t Task) (any, bool) { return t.Due, !t.Due.IsZero() },
+	"created":     func(t Task) (any, bool) { return t.CreatedAt, true },
+	"updated":     func(t Task) (any, bool) { return t.UpdatedAt, !t.UpdatedAt.IsZero() },
+}
+
+// sortAliases lets the short names used in queries work as sort keys too
+var sortAliases = map[string]string{"desc": "description"}
+
+// ParseSort reads comma separated keys such as "priority:desc,due" or
+// "-priority,due", keys are ascending unless marked otherwise
+func ParseSort(s string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{}
+		if name, ok := strings.CutPrefix(part, "-"); ok {
+			part = name
+			key.Desc = true
+		}
+		if name, dir, ok := strings.Cut(part, ":"); ok {
+			switch strings.ToLower(dir) {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("%w direction %q, expected asc or desc", ErrInvalidSort, dir)
+			}
+			part = name
+		}
+		if part == "" {
+			return nil, fmt.Errorf("%w %q, expected keys like priority:desc,due", ErrInvalidSort, s)
+		}
+		key.Field = strings.ToLower(part)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sortFunc resolves sort keys against the built in fields and the custom
+// ones in the schema. Statuses sort in workflow order
+func (s TaskService) sortFunc(keys []SortKey) (func(a, b Task) int, error) {
+	type resolved struct {
+		get  func(t Task) (any, bool)
+		cmp  func(x, y any) int
+		desc bool
+	}
+	var sorts []resolved
+	for _, k := range keys {
+		field := strings.ToLower(k.Field)
+		if alias, ok := sortAliases[field]; ok {
+			field = alias
+		}
+		if field == "status" {
+			sorts = append(sorts, resolved{
+				get:  func(t Task) (any, bool) { return s.workflow.rank(t.Status), true },
+				cmp:  func(x, y any) int { return cmp.Compare(x.(int), y.(int)) },
+				desc: k.Desc,
+			})
+			continue
+		}
+		if get, ok := builtinSorts[field]; ok {
+			sorts = append(sorts, resolved{get: get, cmp: compareBuiltin, desc: k.Desc})
+			continue
+		}
+		def, err := s.schema.lookup(k.Field)
+		if err != nil {
+			return nil, err
+		}
+		sorts = append(sorts, resolved{
+			get: func(t Task) (any, bool) {
+				v, ok := t.Fields[def.Name]
+				return v, ok
+			},
+			cmp:  def.compare,
+			desc: k.Desc,
+		})
+	}
+
+	return func(a, b Task) int {
+		for _, sort := range sorts {
+			x, xok := sort.get(a)
+			y, yok := sort.get(b)
+			switch {
+			case !xok && !yok:
+				continue
+			case !xok:
+				return 1
+			case !yok:
+				return -1
+			}
+			c := sort.cmp(x, y)
+			if sort.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	}, nil
+}
+
+func compareBuiltin(x, y any) int {
+	switch x := x.(type) {
+	case int64:
+		return cmp.Compare(x, y.(int64))
+	case string:
+		return strings.Compare(x, y.(string))
+	case Priority:
+		return cmp.Compare(x, y.(Priority))
+	case Estimate:
+		return cmp.Compare(x, y.(Estimate))
+	case time.Time:
+		return x.Compare(y.(time.Time))
+	}
+	return 0
+}
+
+// page cuts a list sorted by order down to the requested page. After is a
+// cursor, the id of the last task on the previous page, and is applied before
+// Offset. The page starts where the cursor task sorts now, so it still works
+// when that task has since dropped out of the list
+func (o ListOptions) page(tasks, all []Task, order func(a, b Task) int) ([]Task, error) {
+	if o.Limit < 0 || o.Offset < 0 {
+		return nil, fmt.Errorf("%w: limit and offset can't be negative", ErrInvalidCursor)
+	}
+	if o.After != 0 {
+		cursor, err := findTask(all, o.After)
+		if err != nil {
+			return nil, fmt.Errorf("%w: task %d does not exist", ErrInvalidCursor, o.After)
+		}
+		i, found := slices.BinarySearchFunc(tasks, cursor, order)
+		if found {
+			i++
+		}
+		tasks = tasks[i:]
+	}
+	tasks = tasks[min(o.Offset, len(tasks)):]
+	if o.Limit > 0 {
+		tasks = tasks[:min(o.Limit, len(tasks))]
+	}
+	return tasks, nil
+}
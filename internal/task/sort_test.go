@@ -0,0 +1,114 @@
+package task
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []SortKey
+		falsy    bool
+	}{
+		{input: "due", expected: []SortKey{{Field: "due"}}},
+		{input: "Priority:DESC, due:asc", expected: []SortKey{{Field: "priority", Desc: true}, {Field: "due"}}},
+		{input: "-created,id", expected: []SortKey{{Field: "created", Desc: true}, {Field: "id"}}},
+		{input: "due:sideways", falsy: true},
+		{input: "due,,id", falsy: true},
+	}
+
+	for _, tst := range tests {
+		actual, err := ParseSort(tst.input)
+		if tst.falsy {
+			if !errors.Is(err, ErrInvalidSort) {
+				t.Errorf("%q expected %v but got %v", tst.input, ErrInvalidSort, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !slices.Equal(actual, tst.expected) {
+			t.Errorf("%q expected %v but got %v", tst.input, tst.expected, actual)
+		}
+	}
+}
+
+func TestListSortAndPage(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	tasks := []Task{
+		{Description: "banana", Priority: PriorityHigh, Due: now.AddDate(0, 0, 3)},
+		{Description: "Apple", Due: now.AddDate(0, 0, 1)},
+		{Description: "cherry", Priority: PriorityHigh},
+		{Description: "date", Priority: PriorityLow, Due: now.AddDate(0, 0, 2)},
+	}
+	for _, tsk := range tasks {
+		now = now.Add(time.Minute)
+		if _, err := svc.Add(tsk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Mark(4, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(opts ListOptions) func() ([]Task, error) {
+		return func() ([]Task, error) { return svc.List(opts) }
+	}
+	assertIDs(t, "default", list(ListOptions{}), []int64{1, 3, 2, 4})
+	assertIDs(t, "description", list(ListOptions{Sort: []SortKey{{Field: "description"}}}), []int64{2, 1, 3, 4})
+	assertIDs(t, "due desc", list(ListOptions{Sort: []SortKey{{Field: "due", Desc: true}}}), []int64{1, 4, 2, 3})
+	assertIDs(t, "priority then created desc", list(ListOptions{Sort: []SortKey{{Field: "priority", Desc: true}, {Field: "created", Desc: true}}}), []int64{3, 1, 2, 4})
+	assertIDs(t, "status", list(ListOptions{Sort: []SortKey{{Field: "status", Desc: true}, {Field: "id"}}}), []int64{4, 1, 2, 3})
+	if _, err := svc.List(ListOptions{Sort: []SortKey{{Field: "colour"}}}); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("expected %v but got %v", ErrUnknownField, err)
+	}
+
+	byID := []SortKey{{Field: "id"}}
+	assertIDs(t, "limit", list(ListOptions{Sort: byID, Limit: 2}), []int64{1, 2})
+	assertIDs(t, "offset", list(ListOptions{Sort: byID, Limit: 2, Offset: 2}), []int64{3, 4})
+	assertIDs(t, "offset past the end", list(ListOptions{Sort: byID, Offset: 9}), []int64{})
+	assertIDs(t, "after", list(ListOptions{Sort: byID, Limit: 2, After: 2}), []int64{3, 4})
+
+	// A cursor keeps its place when tasks are added before it
+	now = now.Add(time.Minute)
+	if _, err := svc.Add(Task{Description: "aardvark"}); err != nil {
+		t.Fatal(err)
+	}
+	byDesc := []SortKey{{Field: "description"}}
+	assertIDs(t, "after with insert", list(ListOptions{Sort: byDesc, After: 1, Limit: 2}), []int64{3, 4})
+
+	// Or when the cursor task no longer matches the filter
+	todo := StatusTodo
+	if err := svc.Mark(3, StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "after a filtered out task", list(ListOptions{Status: &todo, Sort: byID, After: 3}), []int64{5})
+
+	if _, err := svc.List(ListOptions{After: 42}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected %v but got %v", ErrInvalidCursor, err)
+	}
+}
+
+func TestListSortsStatusesInWorkflowOrder(t *testing.T) {
+	w, err := NewWorkflow([]string{"todo", "in-progress", "review", "done"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	review, _ := w.Parse("review")
+	svc := NewTaskService(WithStore(NewMemoryStore(
+		Task{Id: 1, Description: "Shipped", Status: StatusDone},
+		Task{Id: 2, Description: "In review", Status: review},
+		Task{Id: 3, Description: "Started", Status: StatusInProgress},
+	)), WithWorkflow(w), WithTimeFunction(time.Now))
+
+	assertIDs(t, "status", func() ([]Task, error) {
+		return svc.List(ListOptions{Sort: []SortKey{{Field: "status"}}})
+	}, []int64{3, 2, 1})
+}
@@ -0,0 +1,206 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Store persists the task list behind a TaskService
+type Store interface {
+	Load() ([]Task, error)
+	Save(tasks []Task) error
+	Get(id int64) (Task, error)
+	Put(t Task) error
+}
+
+// ProjectStore is implemented by stores that can keep projects next to the
+// tasks, without it the service has no projects
+type ProjectStore interface {
+	LoadProjects() ([]Project, error)
+	SaveProjects(projects []Project) error
+}
+
+// Locker is implemented by stores that can guard a read-modify-write cycle
+// against other processes
+type Locker interface {
+	Lock(timeout time.Duration) (unlock func(), err error)
+}
+
+// Warner is implemented by stores that recover from problems the user should
+// still hear about
+type Warner interface {
+	Warnings() []error
+}
+
+// Stamper is implemented by stores that can tell the tasks changed without
+// loading them, the stamp is different after every change
+type Stamper interface {
+	Stamp() (string, error)
+}
+
+// fileStore keeps every task in a single JSON file
+type fileStore struct {
+	path     string
+	mu       *sync.Mutex
+	warnings *[]error
+}
+
+func NewFileStore(path string) Store {
+	return fileStore{path: path, mu: &sync.Mutex{}, warnings: &[]error{}}
+}
+
+func (s fileStore) Load() ([]Task, error) {
+	tasks, err := loadOrCreate(s.path)
+	if errors.Is(err, ErrRecovered) {
+		s.warn(err)
+		return tasks, nil
+	}
+	return tasks, err
+}
+
+// warn records a warning once however often the problem is seen
+func (s fileStore) warn(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, w := range *s.warnings {
+		if w.Error() == err.Error() {
+			return
+		}
+	}
+	*s.warnings = append(*s.warnings, err)
+}
+
+func (s fileStore) Warnings() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error{}, *s.warnings...)
+}
+
+func (s fileStore) Save(tasks []Task) error {
+	return save(s.path, tasks)
+}
+
+func (s fileStore) Get(id int64) (Task, error) {
+	tasks, err := s.Load()
+	if err != nil {
+		return Task{}, err
+	}
+	return findTask(tasks, id)
+}
+
+func (s fileStore) Put(t Task) error {
+	tasks, err := s.Load()
+	if err != nil {
+		return err
+	}
+	return s.Save(putTask(tasks, t))
+}
+
+func (s fileStore) LoadProjects() ([]Project, error) {
+	return loadProjects(projectsPath(s.path))
+}
+
+func (s fileStore) SaveProjects(projects []Project) error {
+	return saveProjects(projectsPath(s.path), projects)
+}
+
+// Stamp is the modification time and size of the task file, so edits made by
+// hand count as changes too
+func (s fileStore) Stamp() (string, error) {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+func (s fileStore) Lock(timeout time.Duration) (func(), error) {
+	return lockFile(lockPath(s.path), timeout)
+}
+
+// LockFile takes the lock the file store uses on any other file the CLI
+// rewrites, such as its config. Call unlock to release it
+func LockFile(path string, timeout time.Duration) (unlock func(), err error) {
+	return lockFile(path, timeout)
+}
+
+func lockPath(savePath string) string {
+	return savePath + ".lock"
+}
+
+// memoryStore keeps tasks in memory only, mostly useful for tests
+type memoryStore struct {
+	mu       *sync.Mutex
+	tasks    *[]Task
+	projects *[]Project
+}
+
+func NewMemoryStore(tasks ...Task) Store {
+	initial := append([]Task{}, tasks...)
+	return memoryStore{mu: &sync.Mutex{}, tasks: &initial, projects: &[]Project{}}
+}
+
+func (s memoryStore) Load() ([]Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Task{}, *s.tasks...), nil
+}
+
+func (s memoryStore) Save(tasks []Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.tasks = append([]Task{}, tasks...)
+	return nil
+}
+
+func (s memoryStore) Get(id int64) (Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return findTask(*s.tasks, id)
+}
+
+func (s memoryStore) Put(t Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.tasks = putTask(append([]Task{}, *s.tasks...), t)
+	return nil
+}
+
+func (s memoryStore) LoadProjects() ([]Project, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Project{}, *s.projects...), nil
+}
+
+func (s memoryStore) SaveProjects(projects []Project) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.projects = append([]Project{}, projects...)
+	return nil
+}
+
+func findTask(tasks []Task, id int64) (Task, error) {
+	for _, t := range tasks {
+		if t.Id == id {
+			return t, nil
+		}
+	}
+	return Task{}, fmt.Errorf("%w with id %d", ErrNotFound, id)
+}
+
+// replaces the task with a matching id or appends it when missing
+func putTask(tasks []Task, t Task) []Task {
+	for i := range tasks {
+		if tasks[i].Id == t.Id {
+			tasks[i] = t
+			return tasks
+		}
+	}
+	return append(tasks, t)
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"errors"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestStoreGetPut(t *testing.T) {
+	testTime := time.Now()
+
+	tests := []struct {
+		name          string
+		store         func(fileName string) Store
+		put           []Task
+		getID         int64
+		expected      Task
+		expectedError error
+	}{
+		{
+			name:     "memoryPutThenGet",
+			store:    func(string) Store { return NewMemoryStore() },
+			put:      []Task{{Id: 1, Description: "Test the memory store", CreatedAt: testTime}},
+			getID:    1,
+			expected: Task{Id: 1, Description: "Test the memory store", CreatedAt: testTime},
+		},
+		{
+			name:  "memoryPutReplacesExisting",
+			store: func(string) Store { return NewMemoryStore(Task{Id: 1, Description: "Replace me", CreatedAt: testTime}) },
+			put:   []Task{{Id: 1, Description: "Replaced", Status: StatusDone, CreatedAt: testTime}},
+			getID: 1,
+			expected: Task{
+				Id: 1, Description: "Replaced", Status: StatusDone, CreatedAt: testTime,
+			},
+		},
+		{
+			name:          "memoryErrorWithInvalidID16",
+			store:         func(string) Store { return NewMemoryStore() },
+			getID:         16,
+			expectedError: ErrNotFound,
+		},
+		{
+			name:     "filePutThenGet",
+			store:    func(fileName string) Store { return NewFileStore(fileName) },
+			put:      []Task{{Id: 1, Description: "Test the file store", CreatedAt: testTime}, {Id: 2, Description: "Test the file store again", CreatedAt: testTime}},
+			getID:    2,
+			expected: Task{Id: 2, Description: "Test the file store again", CreatedAt: testTime},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			fileName := "test-" + tst.name + ".json"
+
+			// Cleanup files when done
+			t.Cleanup(func() {
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
+				}
+			})
+
+			store := tst.store(fileName)
+			for _, task := range tst.put {
+				if err := store.Put(task); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			actual, err := store.Get(tst.getID)
+			if err != nil {
+				if !errors.Is(err, tst.expectedError) {
+					t.Error(err)
+				}
+				return
+			}
+			if !isTaskSame(actual, tst.expected) {
+				t.Errorf("%s expected %v but got %v", tst.name, tst.expected, actual)
+			}
+		})
+	}
+}
+
+func TestServiceWithMemoryStore(t *testing.T) {
+	testTime := time.Now()
+	store := NewMemoryStore()
+	svc := NewTaskService(WithStore(store), WithTimeFunction(func() time.Time { return testTime }))
+
+	id, err := svc.Add(Task{Description: "Test the service with a memory store"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Task{{Id: 1, Description: "Test the service with a memory store", Status: StatusDone, CreatedAt: testTime}}
+	if !isTasksSame(tasks, expected) {
+		t.Errorf("expected %v but got %v", expected, tasks)
+	}
+}
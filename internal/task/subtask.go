@@ -0,0 +1,91 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrHasChildren   = errors.New("task has subtasks")
+	ErrOpenChildren  = errors.New("task has unfinished subtasks")
+	ErrParentTrashed = errors.New("parent is in the trash")
+)
+
+// MutationOption tweaks how a single Delete or Mark call behaves
+type MutationOption func(cfg *mutationConfig)
+
+type mutationConfig struct {
+	cascade  bool
+	force    bool
+	parallel bool
+}
+
+// WithCascade applies a Delete or a Mark as done to every subtask as well
+// instead of refusing while subtasks are still around
+func WithCascade() MutationOption {
+	return func(cfg *mutationConfig) {
+		cfg.cascade = true
+	}
+}
+
+func newMutationConfig(opts []MutationOption) mutationConfig {
+	cfg := mutationConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// descendants returns the index of every task below id that is not in the
+// trash, children before grandchildren
+func descendants(tasks []Task, id int64) []int {
+	var found []int
+	parents := []int64{id}
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		for i, t := range tasks {
+			if t.ParentID == parent && !t.Trashed() {
+				found = append(found, i)
+				parents = append(parents, t.Id)
+			}
+		}
+	}
+	return found
+}
+
+// trashedWith returns the index of every subtask below id that went to the
+// trash at the same time, which is what a cascading delete leaves behind
+func trashedWith(tasks []Task, id int64, at time.Time) []int {
+	var found []int
+	parents := []int64{id}
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		for i, t := range tasks {
+			if t.ParentID == parent && t.Trashed() && t.DeletedAt.Equal(at) {
+				found = append(found, i)
+				parents = append(parents, t.Id)
+			}
+		}
+	}
+	return found
+}
+
+// checkParent makes sure a new subtask hangs off a task that exists
+func checkParent(tasks []Task, parentID int64) (Task, error) {
+	i, err := indexOf(tasks, parentID)
+	if err != nil {
+		return Task{}, fmt.Errorf("parent %w", err)
+	}
+	return tasks[i], nil
+}
+
+func (t *Task) setStatus(at time.Time, status Status, w Workflow) {
+	if t.Status == status {
+		return
+	}
+	t.record(at, FieldStatus, w.Name(t.Status), w.Name(status))
+	t.Status = status
+}
@@ -0,0 +1,101 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSubtasks(t *testing.T) {
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(time.Now))
+	projectID, err := svc.AddProject(Project{Name: "home"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, task := range []Task{
+		{Description: "Move house", ProjectID: projectID},
+		{Description: "Pack boxes", ParentID: 1},
+		{Description: "Pack kitchen", ParentID: 2},
+		{Description: "Unrelated"},
+	} {
+		if _, err := svc.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.Add(Task{Description: "Orphan", ParentID: 42}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v for a missing parent but got %v", ErrNotFound, err)
+	}
+
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tasks[2].ProjectID != projectID {
+		t.Errorf("expected subtask to inherit project %d but got %d", projectID, tasks[2].ProjectID)
+	}
+
+	// Parents can't be finished or deleted while children are open
+	if err := svc.Mark(1, StatusDone); !errors.Is(err, ErrOpenChildren) {
+		t.Errorf("expected %v but got %v", ErrOpenChildren, err)
+	}
+	if err := svc.Delete(2); !errors.Is(err, ErrHasChildren) {
+		t.Errorf("expected %v but got %v", ErrHasChildren, err)
+	}
+
+	// Cascading reaches grandchildren
+	if err := svc.Mark(1, StatusDone, WithCascade()); err != nil {
+		t.Fatal(err)
+	}
+	done := StatusDone
+	doneList := func() ([]Task, error) { return svc.List(ListOptions{Status: &done}) }
+	assertIDs(t, "done", doneList, []int64{1, 2, 3})
+
+	if err := svc.Delete(2, WithCascade()); err != nil {
+		t.Fatal(err)
+	}
+	all := func() ([]Task, error) { return svc.List(ListOptions{}) }
+	assertIDs(t, "after cascade delete", all, []int64{1, 4})
+
+	// A single undo brings the whole subtree back
+	if _, err := svc.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "after undo", all, []int64{1, 2, 3, 4})
+
+	// Restoring the top of a cascade brings its subtasks back, restoring a
+	// subtask on its own waits for its parent
+	if err := svc.Delete(2, WithCascade()); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Restore(3); !errors.Is(err, ErrParentTrashed) {
+		t.Errorf("expected %v but got %v", ErrParentTrashed, err)
+	}
+	if err := svc.Restore(2); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "after restore", all, []int64{1, 2, 3, 4})
+
+	// A subtask deleted on its own stays in the trash when its parent is
+	// restored
+	if err := svc.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Restore(2); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "restore leaves separately deleted subtasks", all, []int64{1, 2, 4})
+
+	// One whose parent is gone comes back at the top level
+	deletedAt := timeMustParse(time.RFC3339, "2026-01-14T12:00:00Z")
+	svc = NewTaskService(WithStore(NewMemoryStore(Task{Id: 2, Description: "Orphan", ParentID: 1, DeletedAt: deletedAt})), WithTimeFunction(time.Now))
+	if err := svc.Restore(2); err != nil {
+		t.Fatal(err)
+	}
+	if restored, _ := svc.Get(2); restored.ParentID != 0 || restored.Trashed() {
+		t.Errorf("expected a live top level task but got %+v", restored)
+	}
+}
@@ -0,0 +1,124 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ExtractTags pulls inline +tag words out of a description, returning the
+// description without them and the tags found
+func ExtractTags(description string) (string, []string) {
+	var words, tags []string
+	for _, word := range strings.Fields(description) {
+		if tag, ok := strings.CutPrefix(word, "+"); ok && isValidTag(tag) {
+			tags = append(tags, normalizeTag(tag))
+			continue
+		}
+		words = append(words, word)
+	}
+	if len(tags) == 0 {
+		return description, nil
+	}
+	return strings.Join(words, " "), tags
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(tag)
+}
+
+func isValidTag(tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_/.:", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasTag reports whether the task carries the tag, ignoring case
+func (t Task) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, normalizeTag(tag))
+}
+
+// withTags adds and removes tags, keeping the set sorted and free of duplicates
+func withTags(tags, add, remove []string) []string {
+	set := map[string]bool{}
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	for _, tag := range add {
+		set[normalizeTag(tag)] = true
+	}
+	for _, tag := range remove {
+		delete(set, normalizeTag(tag))
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(set))
+	for tag := range set {
+		result = append(result, tag)
+	}
+	slices.Sort(result)
+	return result
+}
+
+// Tag adds and removes tags on a task
+func (s TaskService) Tag(id int64, add, remove []string) error {
+	for _, tag := range slices.Concat(add, remove) {
+		if !isValidTag(tag) {
+			return fmt.Errorf("%w %q", ErrInvalidTag, tag)
+		}
+	}
+	return s.mutate(EventTag, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i].retag(s.now(), add, remove)
+		return tasks, nil
+	})
+}
+
+func (t *Task) retag(at time.Time, add, remove []string) {
+	tags := withTags(t.Tags, add, remove)
+	if slices.Equal(tags, t.Tags) {
+		return
+	}
+	t.record(at, FieldTags, strings.Join(t.Tags, ","), strings.Join(tags, ","))
+	t.Tags = tags
+	t.UpdatedAt = at
+}
+
+// TagCounts counts how many tasks outside the trash carry each tag
+func (s TaskService) TagCounts() (map[string]int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, t := range tasks {
+		if t.Trashed() {
+			continue
+		}
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,79 @@
+package task
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExtractTags(t *testing.T) {
+	tests := []struct {
+		input               string
+		expectedDescription string
+		expectedTags        []string
+	}{
+		{input: "Deploy the api +work +Backend", expectedDescription: "Deploy the api", expectedTags: []string{"work", "backend"}},
+		{input: "+urgent fix login", expectedDescription: "fix login", expectedTags: []string{"urgent"}},
+		{input: "1 + 1 is not a tag", expectedDescription: "1 + 1 is not a tag"},
+		{input: "C++ is not a tag either", expectedDescription: "C++ is not a tag either"},
+	}
+
+	for _, tst := range tests {
+		description, tags := ExtractTags(tst.input)
+		if description != tst.expectedDescription {
+			t.Errorf("%q expected description %q but got %q", tst.input, tst.expectedDescription, description)
+		}
+		if !slices.Equal(tags, tst.expectedTags) {
+			t.Errorf("%q expected tags %v but got %v", tst.input, tst.expectedTags, tags)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(time.Now))
+
+	for _, desc := range []string{"Write docs +work +docs", "Fix build +work +blocked", "Buy milk +home"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Update(3, Task{Description: "Buy oat milk +shopping"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Tag(2, []string{"ci"}, []string{"blocked"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Tag(1, []string{"not a tag"}, nil); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected %v but got %v", ErrInvalidTag, err)
+	}
+
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int64][]string{1: {"docs", "work"}, 2: {"ci", "work"}, 3: {"home", "shopping"}}
+	for _, task := range tasks {
+		if !slices.Equal(task.Tags, expected[task.Id]) {
+			t.Errorf("task %d expected tags %v but got %v", task.Id, expected[task.Id], task.Tags)
+		}
+	}
+	if tasks[2].Description != "Buy oat milk" {
+		t.Errorf("expected tags stripped from description but got %q", tasks[2].Description)
+	}
+
+	filtered := func() ([]Task, error) {
+		return svc.List(ListOptions{Tags: []string{"WORK"}, ExcludeTags: []string{"ci"}})
+	}
+	assertIDs(t, "filtered", filtered, []int64{1})
+
+	counts, err := svc.TagCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCounts := map[string]int{"work": 2, "docs": 1, "ci": 1, "home": 1, "shopping": 1}
+	if !maps.Equal(counts, expectedCounts) {
+		t.Errorf("expected counts %v but got %v", expectedCounts, counts)
+	}
+}
@@ -3,15 +3,49 @@ package task
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 )
 
 type Tasker interface {
 	Add(Task) (int64, error)
 	Update(id int64, t Task) error
-	Delete(id int64) error
-	Mark(id int64, status Status) error
-	List(status *Status) ([]Task, error)
+	Delete(id int64, opts ...MutationOption) error
+	Mark(id int64, status Status, opts ...MutationOption) error
+	Prioritize(id int64, priority Priority) error
+	SetDue(id int64, due time.Time) error
+	Tag(id int64, add, remove []string) error
+	TagCounts() (map[string]int, error)
+	SetRecurrence(id int64, r *Recurrence) error
+	Start(id int64, opts ...MutationOption) error
+	Stop(id int64) error
+	TimeReport() (TimeReport, error)
+	SetEstimate(id int64, e Estimate) error
+	EstimateReport() (EstimateReport, error)
+	AddNote(id int64, text string) error
+	Block(id, on int64) error
+	Unblock(id, on int64) error
+	Get(id int64) (Task, error)
+	List(opts ListOptions) ([]Task, error)
+	AddProject(p Project) (int64, error)
+	Projects() ([]Project, error)
+	FindProject(name string) (Project, error)
+	ArchiveProject(name string) error
+	AssignProject(id, projectID int64) error
+	Trash() ([]Task, error)
+	Restore(id int64) error
+	Purge(olderThan time.Duration) (int, error)
+	History(id int64) ([]Change, error)
+	Undo() ([]Event, error)
+	Redo() ([]Event, error)
+	Workflow() Workflow
+	SetFields(id int64, values map[string]string) error
+	Schema() Schema
+	Search(query string, limit int) ([]SearchResult, error)
+	Reindex() error
+	Warnings() []error
+	Now() time.Time
 }
 
 type Status int
@@ -35,21 +69,47 @@ const (
 )
 
 var (
-	ErrNotFound = errors.New("task not found")
+	ErrNotFound    = errors.New("task not found")
+	ErrLockTimeout = errors.New("timed out waiting for the task file lock")
 )
 
+// DefaultLockTimeout is how long a TaskService waits for another process to
+// release the task file
+const DefaultLockTimeout = 5 * time.Second
+
 type Task struct {
-	Id          int64     `json:"id"`
-	Description string    `json:"description"`
-	Status      Status    `json:"status"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt,omitzero"`
+	Id          int64          `json:"id"`
+	Description string         `json:"description"`
+	Status      Status         `json:"status"`
+	Priority    Priority       `json:"priority,omitzero"`
+	Tags        []string       `json:"tags,omitempty"`
+	ProjectID   int64          `json:"projectId,omitzero"`
+	ParentID    int64          `json:"parentId,omitzero"`
+	BlockedBy   []int64        `json:"blockedBy,omitempty"`
+	Recurrence  *Recurrence    `json:"recurrence,omitempty"`
+	Estimate    Estimate       `json:"estimate,omitzero"`
+	TimeLog     []Interval     `json:"timeLog,omitempty"`
+	Notes       []Note         `json:"notes,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	Due         time.Time      `json:"due,omitzero"`
+	UpdatedAt   time.Time      `json:"updatedAt,omitzero"`
+	DeletedAt   time.Time      `json:"deletedAt,omitzero"`
+	History     []Change       `json:"history,omitempty"`
 }
 
 type NowFunc func() time.Time
 type TaskService struct {
-	savePath string
-	now      NowFunc
+	savePath    string
+	store       Store
+	journal     Journal
+	now         NowFunc
+	lockTimeout time.Duration
+	workflow    Workflow
+	schema      Schema
+	index       IndexStore
+	// warnings collects problems found while running, see Warnings
+	warnings *[]error
 }
 
 type TaskServiceOption func(svc *TaskService)
@@ -60,6 +120,28 @@ func WithSavePath(path string) TaskServiceOption {
 	}
 }
 
+// WithStore swaps the default JSON file storage for another backend
+func WithStore(store Store) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.store = store
+	}
+}
+
+// WithJournal sets where mutation events are recorded for undo and redo
+func WithJournal(journal Journal) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.journal = journal
+	}
+}
+
+// WithLockTimeout sets how long to wait for the store lock before giving up
+// with ErrLockTimeout
+func WithLockTimeout(timeout time.Duration) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.lockTimeout = timeout
+	}
+}
+
 func WithTimeFunction(f NowFunc) TaskServiceOption {
 	return func(svc *TaskService) {
 		svc.now = f
@@ -67,125 +149,454 @@ func WithTimeFunction(f NowFunc) TaskServiceOption {
 }
 
 func NewTaskService(opts ...TaskServiceOption) Tasker {
-	svc := TaskService{}
+	svc := TaskService{lockTimeout: DefaultLockTimeout, warnings: &[]error{}}
 	for _, opt := range opts {
 		opt(&svc)
 	}
+	if svc.store == nil {
+		svc.store = NewFileStore(svc.savePath)
+		if svc.journal == nil {
+			svc.journal = NewFileJournal(journalPath(svc.savePath))
+		}
+		if svc.index == nil {
+			svc.index = NewFileIndex(indexPath(svc.savePath))
+		}
+	}
+	if svc.journal == nil {
+		svc.journal = NewMemoryJournal()
+	}
+	if svc.index == nil {
+		svc.index = NewMemoryIndex()
+	}
+	if svc.workflow.names == nil {
+		svc.workflow = DefaultWorkflow()
+	}
 	return svc
 }
 
+// Now is the service's current time, use it to resolve relative dates so they
+// agree with WithTimeFunction
+func (s TaskService) Now() time.Time {
+	return s.now()
+}
+
+// Warnings lists problems to show the user: tasks on a status the workflow
+// no longer has, a journal that doesn't match the tasks and anything the
+// store recovered from, such as reading tasks from the backup
+func (s TaskService) Warnings() []error {
+	var warnings []error
+	if tasks, err := s.store.Load(); err == nil {
+		warnings = s.workflow.unknownStatuses(tasks)
+	}
+	warnings = append(warnings, *s.warnings...)
+	if w, ok := s.store.(Warner); ok {
+		warnings = append(warnings, w.Warnings()...)
+	}
+	return warnings
+}
+
+// Get returns a single task, including one sitting in the trash. A missing id
+// is ErrNotFound
+func (s TaskService) Get(id int64) (Task, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return Task{}, err
+	}
+	defer unlock()
+
+	t, err := s.store.Get(id)
+	if err != nil {
+		return Task{}, err
+	}
+	return t.clone(), nil
+}
+
 func (s TaskService) Add(t Task) (int64, error) {
-	tasks, err := loadOrCreate(s.savePath)
+	err := s.mutate(EventAdd, func(tasks []Task) ([]Task, error) {
+		// Subtasks live in the same project as their parent unless told otherwise
+		if t.ParentID != 0 {
+			parent, err := checkParent(tasks, t.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if t.ProjectID == 0 {
+				t.ProjectID = parent.ProjectID
+			}
+		}
+		if err := s.checkProject(t.ProjectID); err != nil {
+			return nil, err
+		}
+
+		// Fill in the tasks blanks
+		maxID, err := s.highestID(tasks)
+		if err != nil {
+			return nil, err
+		}
+		t.Id = maxID + 1
+		t.CreatedAt = s.now()
+		description, tags := ExtractTags(t.Description)
+		t.Description = description
+		t.Tags = withTags(nil, slices.Concat(t.Tags, tags), nil)
+		t.Recurrence = t.Recurrence.anchor(t.Due, t.CreatedAt)
+		t.History = nil
+		t.record(t.CreatedAt, FieldStatus, "", s.workflow.Name(t.Status))
+		return append(tasks, t), nil
+	})
 	if err != nil {
 		return 0, err
 	}
+	return t.Id, nil
+}
 
-	// Fill in the tasks blanks
-	var maxID int64 = 0
-	for _, t := range tasks {
-		if t.Id > maxID {
-			maxID = t.Id
+func (s TaskService) Update(id int64, t Task) error {
+	return s.mutate(EventUpdate, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		now := s.now()
+		description, tags := ExtractTags(t.Description)
+		tasks[i].retag(now, tags, nil)
+		if tasks[i].Description == description {
+			return tasks, nil
+		}
+		tasks[i].record(now, FieldDescription, tasks[i].Description, description)
+		tasks[i].Description = description
+		tasks[i].UpdatedAt = now
+		return tasks, nil
+	})
+}
+
+// Delete moves a task to the trash. Tasks with subtasks are refused unless
+// WithCascade is given, which trashes the whole subtree
+func (s TaskService) Delete(id int64, opts ...MutationOption) error {
+	cfg := newMutationConfig(opts)
+	return s.mutate(EventDelete, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		children := descendants(tasks, id)
+		if len(children) > 0 && !cfg.cascade {
+			return nil, fmt.Errorf("%w (%d) with id %d", ErrHasChildren, len(children), id)
+		}
+		// Dependencies are kept so a restore brings them back, trashed
+		// blockers don't hold anything up in the meantime
+		now := s.now()
+		for _, child := range children {
+			tasks[child].DeletedAt = now
+		}
+		tasks[i].DeletedAt = now
+		return tasks, nil
+	})
+}
+
+// Mark sets the status of a task, moves the workflow doesn't allow are refused
+// with ErrInvalidTransition. Marking a task done while it has unfinished
+// subtasks is refused unless WithCascade is given, which marks them done too.
+// Starting a task with unfinished blockers is refused unless WithForce is given
+func (s TaskService) Mark(id int64, status Status, opts ...MutationOption) error {
+	cfg := newMutationConfig(opts)
+	return s.mutate(EventMark, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.workflow.checkTransition(id, tasks[i].Status, status); err != nil {
+			return nil, err
 		}
+		now := s.now()
+		if status == StatusInProgress && !cfg.force {
+			if open := openBlockers(tasks[i], tasksByID(tasks)); len(open) > 0 {
+				return nil, fmt.Errorf("%w by %s with id %d", ErrBlocked, joinIDs(open), id)
+			}
+		}
+		if status == StatusDone {
+			var open []int
+			for _, child := range descendants(tasks, id) {
+				if tasks[child].Status != StatusDone {
+					open = append(open, child)
+				}
+			}
+			if len(open) > 0 && !cfg.cascade {
+				return nil, fmt.Errorf("%w (%d) with id %d", ErrOpenChildren, len(open), id)
+			}
+			for _, child := range open {
+				if err := s.workflow.checkTransition(tasks[child].Id, tasks[child].Status, StatusDone); err != nil {
+					return nil, fmt.Errorf("subtask %w", err)
+				}
+				tasks, err = s.finish(tasks, child, now)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return s.finish(tasks, i, now)
+		}
+		tasks[i].setStatus(now, status, s.workflow)
+		return tasks, nil
+	})
+}
+
+// finish marks the task at index i done, stops its timer and queues up the
+// next occurrence when it repeats
+func (s TaskService) finish(tasks []Task, i int, now time.Time) ([]Task, error) {
+	if tasks[i].Status == StatusDone {
+		return tasks, nil
+	}
+	tasks[i].setStatus(now, StatusDone, s.workflow)
+	if tasks[i].Running() {
+		tasks[i].stopTimer(now)
+	}
+	if tasks[i].Recurrence == nil {
+		return tasks, nil
 	}
-	t.Id = maxID + 1
-	t.CreatedAt = s.now()
+	maxID, err := s.highestID(tasks)
+	if err != nil {
+		return nil, err
+	}
+	// Hand the rule over so reopening and finishing this one again can't
+	// spawn a second copy
+	next := nextOccurrence(tasks[i], maxID+1, now)
+	tasks[i].record(now, FieldRecurrence, tasks[i].Recurrence.describe(), "")
+	tasks[i].Recurrence = nil
+	return append(tasks, next), nil
+}
 
-	tasks = append(tasks, t)
-	err = save(s.savePath, tasks)
+// Undo reverts the most recent mutation that has not been undone yet and
+// returns the events it reverted
+func (s TaskService) Undo() ([]Event, error) {
+	return s.rewind(EventUndo)
+}
 
-	return t.Id, err
+// Redo reapplies the most recently undone mutation and returns its events
+func (s TaskService) Redo() ([]Event, error) {
+	return s.rewind(EventRedo)
 }
 
-func (s TaskService) Update(id int64, t Task) error {
-	tasks, err := loadOrCreate(s.savePath)
+func (s TaskService) rewind(marker EventType) ([]Event, error) {
+	unlock, err := s.lock()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer unlock()
 
-	// Find the task to update
-	found := false
-	for i, task := range tasks {
-		if task.Id != id {
-			continue
+	stamp, err := s.stamp()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.journalEvents(tasks)
+	if err != nil {
+		return nil, err
+	}
+	done, undone := replayStacks(events)
+
+	var tx int64
+	switch marker {
+	case EventUndo:
+		if len(done) == 0 {
+			return nil, ErrNothingToUndo
+		}
+		tx = done[len(done)-1]
+	case EventRedo:
+		if len(undone) == 0 {
+			return nil, ErrNothingToRedo
 		}
-		found = true
-		task.Description = t.Description
-		task.UpdatedAt = s.now()
-		tasks[i] = task
+		tx = undone[len(undone)-1]
 	}
-	if !found {
-		return fmt.Errorf("%w with id %d", ErrNotFound, id)
+	txEvents := eventsInTx(events, tx)
+
+	if marker == EventUndo {
+		// Walk backwards so a task touched twice ends up at its oldest state
+		for i := len(txEvents) - 1; i >= 0; i-- {
+			tasks = applySnapshot(tasks, txEvents[i].TaskID, txEvents[i].Before)
+		}
+	} else {
+		for _, e := range txEvents {
+			tasks = applySnapshot(tasks, e.TaskID, e.After)
+		}
 	}
-	return save(s.savePath, tasks)
+
+	marked := Event{Tx: lastTx(events) + 1, Type: marker, At: s.now(), Ref: tx}
+	if err := s.commit(events, []Event{marked}, tasks); err != nil {
+		return nil, err
+	}
+	s.updateIndex(tasks, changedIDs(txEvents), stamp)
+	return txEvents, nil
 }
 
-func (s TaskService) Delete(id int64) error {
-	tasks, err := loadOrCreate(s.savePath)
+// mutate runs fn against a copy of the task list under the store lock, then
+// journals whatever changed and saves the result
+func (s TaskService) mutate(eventType EventType, fn func(tasks []Task) ([]Task, error)) error {
+	unlock, err := s.lock()
 	if err != nil {
 		return err
 	}
-	// Find and delete the task
-	found := false
-	for i, task := range tasks {
-		if task.Id != id {
-			continue
-		}
-		found = true
-		t2 := append(tasks[:i], tasks[i+1:]...)
-		tasks = t2
-		break
+	defer unlock()
+
+	stamp, err := s.stamp()
+	if err != nil {
+		return err
 	}
-	if !found {
-		return fmt.Errorf("%w with id %d", ErrNotFound, id)
+	before, err := s.store.Load()
+	if err != nil {
+		return err
+	}
+	after, err := fn(cloneTasks(before))
+	if err != nil {
+		return err
 	}
-	return save(s.savePath, tasks)
-}
 
-func (s TaskService) Mark(id int64, status Status) error {
-	tasks, err := loadOrCreate(s.savePath)
+	changes, err := diffTasks(eventType, before, after)
 	if err != nil {
 		return err
 	}
-	// Find and update the task status
-	found := false
-	for i, task := range tasks {
-		if task.Id != id {
-			continue
+	if len(changes) == 0 {
+		return nil
+	}
+	events, err := s.journalEvents(before)
+	if err != nil {
+		return err
+	}
+	tx := lastTx(events) + 1
+	at := s.now()
+	for i := range changes {
+		changes[i].Tx = tx
+		changes[i].At = at
+	}
+	if err := s.commit(events, changes, after); err != nil {
+		return err
+	}
+	s.updateIndex(after, changedIDs(changes), stamp)
+	return nil
+}
+
+// commit journals the new events before saving tasks, so the journal is never
+// behind the store. When the save fails the events are taken back out, and a
+// crash in between is caught by journalEvents on the next write
+func (s TaskService) commit(events, added []Event, tasks []Task) error {
+	if err := s.journal.Append(added...); err != nil {
+		return err
+	}
+	rewriter, canRewrite := s.journal.(Rewriter)
+	if err := s.store.Save(tasks); err != nil {
+		if canRewrite {
+			if rollbackErr := rewriter.Rewrite(events); rollbackErr != nil {
+				return errors.Join(err, rollbackErr)
+			}
 		}
-		found = true
-		task.Status = status
-		tasks[i] = task
-		break
+		return err
 	}
-	if !found {
-		return fmt.Errorf("%w with id %d", ErrNotFound, id)
+	if canRewrite && len(events)+len(added) > maxJournalEvents {
+		// The change is saved, a failed compaction is retried next time
+		_ = rewriter.Rewrite(compactEvents(append(events, added...), undoDepth))
 	}
-	return save(s.savePath, tasks)
+	return nil
 }
 
-func (s TaskService) List(status *Status) ([]Task, error) {
-	tasks, err := loadOrCreate(s.savePath)
+// journalEvents reads the journal, dropping a last transaction that never
+// made it into tasks. When the tasks were changed outside the service the
+// journal is kept as it is and a warning recorded
+func (s TaskService) journalEvents(tasks []Task) ([]Event, error) {
+	events, err := s.journal.Events()
 	if err != nil {
 		return nil, err
 	}
-	// Return blank or unfiltered list
-	if len(tasks) == 0 || status == nil {
-		return tasks, nil
+	rewriter, ok := s.journal.(Rewriter)
+	if !ok {
+		return events, nil
+	}
+	start, found, diverged, err := unapplied(events, tasks)
+	if diverged && !slices.ContainsFunc(*s.warnings, func(err error) bool { return errors.Is(err, ErrJournalDiverged) }) {
+		*s.warnings = append(*s.warnings, fmt.Errorf("%w since the last change, undo only reverts changes made by task-cli", ErrJournalDiverged))
+	}
+	if err != nil || !found {
+		return events, err
+	}
+	events = events[:start]
+	return events, rewriter.Rewrite(events)
+}
+
+func changedIDs(events []Event) []int64 {
+	ids := make([]int64, len(events))
+	for i, e := range events {
+		ids[i] = e.TaskID
+	}
+	return ids
+}
+
+// lock guards a whole read-modify-write cycle when the store supports it
+func (s TaskService) lock() (func(), error) {
+	locker, ok := s.store.(Locker)
+	if !ok {
+		return func() {}, nil
+	}
+	return locker.Lock(s.lockTimeout)
+}
+
+// clone copies the task so changes to the copy never leak into the original
+func (t Task) clone() Task {
+	t.Tags = slices.Clone(t.Tags)
+	t.BlockedBy = slices.Clone(t.BlockedBy)
+	t.TimeLog = slices.Clone(t.TimeLog)
+	t.Notes = slices.Clone(t.Notes)
+	t.Fields = maps.Clone(t.Fields)
+	if t.Recurrence != nil {
+		r := *t.Recurrence
+		r.Weekdays = slices.Clone(r.Weekdays)
+		t.Recurrence = &r
 	}
+	t.History = slices.Clone(t.History)
+	return t
+}
+
+func cloneTasks(tasks []Task) []Task {
+	cloned := make([]Task, len(tasks))
+	for i, t := range tasks {
+		cloned[i] = t.clone()
+	}
+	return cloned
+}
+
+// highestID finds the largest id ever handed out, including tasks that have
+// since been purged and only live on in the journal
+func (s TaskService) highestID(tasks []Task) (int64, error) {
+	var maxID int64 = 0
+	for _, t := range tasks {
+		if t.Id > maxID {
+			maxID = t.Id
+		}
+	}
+	events, err := s.journal.Events()
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range events {
+		if e.TaskID > maxID {
+			maxID = e.TaskID
+		}
+	}
+	return maxID, nil
+}
 
-	// filter list by status
-	var filteredList []Task
-	for _, task := range tasks {
-		if task.Status != *status {
-			continue
+// indexOf finds a task that has not been trashed
+func indexOf(tasks []Task, id int64) (int, error) {
+	for i, t := range tasks {
+		if t.Id == id && !t.Trashed() {
+			return i, nil
 		}
-		filteredList = append(filteredList, task)
 	}
-	return filteredList, nil
+	return 0, fmt.Errorf("%w with id %d", ErrNotFound, id)
 }
 
 func loadOrCreate(path string) ([]Task, error) {
 	tasks, err := load(path)
+	if errors.Is(err, ErrRecovered) {
+		return tasks, err
+	}
 	if err != nil {
 		if !errors.Is(err, ErrFileNotExist) {
 			return nil, err
@@ -31,14 +31,14 @@ func TestAdd(t *testing.T) {
 			preExistingFileContent: ``,
 			newTask:                Task{Description: "Test The add function", Status: StatusInProgress},
 			expectedID:             1,
-			expectedFileContent:    `[{"id":1,"description":"Test The add function","status":1,"createdAt":` + string(timeBytes) + `}]`,
+			expectedFileContent:    `[{"id":1,"description":"Test The add function","status":1,"createdAt":` + string(timeBytes) + `,"history":[{"at":` + string(timeBytes) + `,"field":"status","to":"in-progress"}]}]`,
 		},
 		{
 			name:                   "tasksAppendAsID2",
 			preExistingFileContent: `[{"id":1,"description":"Test The add function","status":1,"createdAt":` + string(timeBytes) + `}]`,
 			newTask:                Task{Description: "Test The add function appends stuff", Status: StatusTodo},
 			expectedID:             2,
-			expectedFileContent:    `[{"id":1,"description":"Test The add function","status":1,"createdAt":` + string(timeBytes) + `},{"id":2,"description":"Test The add function appends stuff","status":0,"createdAt":` + string(timeBytes) + `}]`,
+			expectedFileContent:    `[{"id":1,"description":"Test The add function","status":1,"createdAt":` + string(timeBytes) + `},{"id":2,"description":"Test The add function appends stuff","status":0,"createdAt":` + string(timeBytes) + `,"history":[{"at":` + string(timeBytes) + `,"field":"status","to":"todo"}]}]`,
 		},
 	}
 
@@ -48,8 +48,10 @@ func TestAdd(t *testing.T) {
 
 			// Cleanup files when done
 			t.Cleanup(func() {
-				if err := deleteFile(fileName); err != nil {
-					log.Default().Print(err)
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
 				}
 			})
 
@@ -103,7 +105,7 @@ func TestUpdate(t *testing.T) {
 			name:                   "tasksUpdateDescriptionAndStatus",
 			preExistingFileContent: `[{"id":1,"description":"Test The update function","status":1,"createdAt":` + string(timeBytes) + `}]`,
 			newTask:                Task{Id: 1, Description: "Test The update function works right", Status: StatusDone},
-			expectedFileContent:    fmt.Sprintf(`[{"id":1,"description":"Test The update function works right","status":2,"createdAt":%s,"updatedAt":%s}]`, string(timeBytes), string(timeBytes)),
+			expectedFileContent:    fmt.Sprintf(`[{"id":1,"description":"Test The update function works right","status":2,"createdAt":%s,"updatedAt":%s,"history":[{"at":%s,"field":"description","from":"Test The update function","to":"Test The update function works right"}]}]`, string(timeBytes), string(timeBytes), string(timeBytes)),
 		},
 		{
 			name:                   "tasksErrorWithInvalidID16",
@@ -119,8 +121,10 @@ func TestUpdate(t *testing.T) {
 
 			// Cleanup files when done
 			t.Cleanup(func() {
-				if err := deleteFile(fileName); err != nil {
-					log.Default().Print(err)
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
 				}
 			})
 
@@ -170,10 +174,10 @@ func TestDelete(t *testing.T) {
 		preventCleanup         bool
 	}{
 		{
-			name:                   "tasksDeleteFromFile",
+			name:                   "tasksDeleteMovesToTrash",
 			preExistingFileContent: `[{"id":1,"description":"Test The delete function","status":1,"createdAt":` + string(timeBytes) + `}]`,
 			id:                     1,
-			expectedFileContent:    `[]`,
+			expectedFileContent:    fmt.Sprintf(`[{"id":1,"description":"Test The delete function","status":1,"createdAt":%s,"deletedAt":%s}]`, string(timeBytes), string(timeBytes)),
 		},
 		{
 			name:                   "tasksErrorWithInvalidID16",
@@ -182,10 +186,10 @@ func TestDelete(t *testing.T) {
 			expectedError:          ErrNotFound,
 		},
 		{
-			name:                   "tasksDeleteFromFileAndLeavesTheRest",
+			name:                   "tasksDeleteMovesToTrashAndLeavesTheRest",
 			preExistingFileContent: `[{"id":1,"description":"Test The delete function deletes this","status":1,"createdAt":` + string(timeBytes) + `},{"id":2,"description":"Test The delete function leaves this","status":2,"createdAt":` + string(timeBytes) + `}]`,
 			id:                     1,
-			expectedFileContent:    fmt.Sprintf(`[{"id":2,"description":"Test The delete function leaves this","status":2,"createdAt":%s}]`, string(timeBytes)),
+			expectedFileContent:    fmt.Sprintf(`[{"id":1,"description":"Test The delete function deletes this","status":1,"createdAt":%s,"deletedAt":%s},{"id":2,"description":"Test The delete function leaves this","status":2,"createdAt":%s}]`, string(timeBytes), string(timeBytes), string(timeBytes)),
 		},
 	}
 
@@ -195,8 +199,10 @@ func TestDelete(t *testing.T) {
 
 			// Cleanup files when done
 			t.Cleanup(func() {
-				if err := deleteFile(fileName); err != nil {
-					log.Default().Print(err)
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
 				}
 			})
 
@@ -251,7 +257,7 @@ func TestMark(t *testing.T) {
 			preExistingFileContent: `[{"id":1,"description":"Test The mark function","status":1,"createdAt":` + string(timeBytes) + `}]`,
 			id:                     1,
 			newStatus:              2,
-			expectedFileContent:    `[{"id":1,"description":"Test The mark function","status":2,"createdAt":` + string(timeBytes) + `}]`,
+			expectedFileContent:    `[{"id":1,"description":"Test The mark function","status":2,"createdAt":` + string(timeBytes) + `,"history":[{"at":` + string(timeBytes) + `,"field":"status","from":"in-progress","to":"done"}]}]`,
 		},
 		{
 			name:                   "tasksErrorWithInvalidID16",
@@ -268,8 +274,10 @@ func TestMark(t *testing.T) {
 
 			// Cleanup files when done
 			t.Cleanup(func() {
-				if err := deleteFile(fileName); err != nil {
-					log.Default().Print(err)
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
 				}
 			})
 
@@ -350,8 +358,10 @@ func TestList(t *testing.T) {
 
 			// Cleanup files when done
 			t.Cleanup(func() {
-				if err := deleteFile(fileName); err != nil {
-					log.Default().Print(err)
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
 				}
 			})
 
@@ -364,7 +374,7 @@ func TestList(t *testing.T) {
 
 			// Add a task
 			svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(func() time.Time { return testTime }))
-			tasks, err := svc.List(tst.filter)
+			tasks, err := svc.List(ListOptions{Status: tst.filter})
 			if err != nil {
 				if errors.Is(err, tst.expectedError) {
 					return
@@ -386,3 +396,81 @@ func TestList(t *testing.T) {
 		})
 	}
 }
+
+func TestGet(t *testing.T) {
+	testTime := time.Now()
+	timeBytes, err := testTime.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                   string
+		preExistingFileContent string
+		id                     int64
+		expectedTask           Task
+		expectedError          error
+	}{
+		{
+			name:          "tasksGetWithNoFile",
+			id:            1,
+			expectedError: ErrNotFound,
+		},
+		{
+			name: "tasksGetExisting",
+			preExistingFileContent: `[{"id":1,"description":"Test The get function skips this","status":0,"createdAt":` + string(timeBytes) + `},
+{"id":2,"description":"Test The get function","status":1,"createdAt":` + string(timeBytes) + `}]`,
+			id:           2,
+			expectedTask: Task{Id: 2, Description: "Test The get function", Status: 1, CreatedAt: testTime},
+		},
+		{
+			name:                   "tasksGetTrashed",
+			preExistingFileContent: `[{"id":1,"description":"Test The get function","status":0,"createdAt":` + string(timeBytes) + `,"deletedAt":` + string(timeBytes) + `}]`,
+			id:                     1,
+			expectedTask:           Task{Id: 1, Description: "Test The get function", Status: 0, CreatedAt: testTime, DeletedAt: testTime},
+		},
+		{
+			name:                   "tasksGetMissing",
+			preExistingFileContent: `[{"id":1,"description":"Test The get function","status":0,"createdAt":` + string(timeBytes) + `}]`,
+			id:                     2,
+			expectedError:          ErrNotFound,
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			fileName := "test-" + tst.name + ".json"
+
+			// Cleanup files when done
+			t.Cleanup(func() {
+				for _, f := range testFiles(fileName) {
+					if err := deleteFile(f); err != nil {
+						log.Default().Print(err)
+					}
+				}
+			})
+
+			// Create needed pre-test files
+			if tst.preExistingFileContent != "" {
+				if err := os.WriteFile(fileName, []byte(tst.preExistingFileContent), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			svc := NewTaskService(WithSavePath(fileName), WithTimeFunction(func() time.Time { return testTime }))
+			task, err := svc.Get(tst.id)
+			if tst.expectedError != nil {
+				if !errors.Is(err, tst.expectedError) {
+					t.Errorf("%s expected error %v but got %v", tst.name, tst.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !isTaskSame(task, tst.expectedTask) || !task.DeletedAt.Equal(tst.expectedTask.DeletedAt) {
+				t.Errorf("%s expected %v but got %v", tst.name, tst.expectedTask, task)
+			}
+		})
+	}
+}
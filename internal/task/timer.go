@@ -0,0 +1,174 @@
+package task
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrTimerRunning    = errors.New("timer already running")
+	ErrTimerNotRunning = errors.New("no timer running")
+)
+
+// Interval is a stretch of time tracked against a task, End is zero while the
+// timer is still running
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitzero"`
+}
+
+// Duration of the interval, counting a running timer up until now
+func (i Interval) Duration(now time.Time) time.Duration {
+	if i.End.IsZero() {
+		return now.Sub(i.Start)
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Running reports whether the task has a timer going
+func (t Task) Running() bool {
+	return len(t.TimeLog) > 0 && t.TimeLog[len(t.TimeLog)-1].End.IsZero()
+}
+
+// Tracked adds up every interval on the task
+func (t Task) Tracked(now time.Time) time.Duration {
+	var total time.Duration
+	for _, i := range t.TimeLog {
+		total += i.Duration(now)
+	}
+	return total
+}
+
+// WithParallelTimers lets Start leave timers on other tasks running
+func WithParallelTimers() MutationOption {
+	return func(cfg *mutationConfig) {
+		cfg.parallel = true
+	}
+}
+
+// Start begins tracking time against a task and marks it in progress. Any other
+// running timer is stopped first unless WithParallelTimers is given
+func (s TaskService) Start(id int64, opts ...MutationOption) error {
+	cfg := newMutationConfig(opts)
+	return s.mutate(EventStart, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if tasks[i].Running() {
+			return nil, fmt.Errorf("%w on task %d", ErrTimerRunning, id)
+		}
+		if err := s.workflow.checkTransition(id, tasks[i].Status, StatusInProgress); err != nil {
+			return nil, err
+		}
+		if tasks[i].Status != StatusInProgress && !cfg.force {
+			if open := openBlockers(tasks[i], tasksByID(tasks)); len(open) > 0 {
+				return nil, fmt.Errorf("%w by %s with id %d", ErrBlocked, joinIDs(open), id)
+			}
+		}
+
+		now := s.now()
+		if !cfg.parallel {
+			for j := range tasks {
+				if tasks[j].Running() {
+					tasks[j].stopTimer(now)
+				}
+			}
+		}
+		tasks[i].TimeLog = append(tasks[i].TimeLog, Interval{Start: now})
+		tasks[i].setStatus(now, StatusInProgress, s.workflow)
+		return tasks, nil
+	})
+}
+
+// Stop ends the running timer on a task
+func (s TaskService) Stop(id int64) error {
+	return s.mutate(EventStop, func(tasks []Task) ([]Task, error) {
+		i, err := indexOf(tasks, id)
+		if err != nil {
+			return nil, err
+		}
+		if !tasks[i].Running() {
+			return nil, fmt.Errorf("%w on task %d", ErrTimerNotRunning, id)
+		}
+		tasks[i].stopTimer(s.now())
+		return tasks, nil
+	})
+}
+
+// byDay splits the interval at local midnight in now's location
+func (i Interval) byDay(now time.Time) map[string]time.Duration {
+	days := map[string]time.Duration{}
+	loc := now.Location()
+	start := i.Start.In(loc)
+	end := i.Start.Add(i.Duration(now)).In(loc)
+	for start.Before(end) {
+		stop := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+		if end.Before(stop) {
+			stop = end
+		}
+		days[start.Format(time.DateOnly)] += stop.Sub(start)
+		start = stop
+	}
+	return days
+}
+
+func (t *Task) stopTimer(at time.Time) {
+	t.TimeLog[len(t.TimeLog)-1].End = at
+}
+
+// TimeReport sums tracked time per task, per tag and per day
+type TimeReport struct {
+	Total  time.Duration
+	ByTask []TaskTime
+	ByTag  map[string]time.Duration
+	// ByDay is keyed by local date as YYYY-MM-DD, intervals running past
+	// midnight are split between the days
+	ByDay map[string]time.Duration
+}
+
+type TaskTime struct {
+	Task     Task
+	Duration time.Duration
+}
+
+// TimeReport adds up every interval tracked against tasks outside the trash,
+// running timers count up until now
+func (s TaskService) TimeReport() (TimeReport, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return TimeReport{}, err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil {
+		return TimeReport{}, err
+	}
+
+	now := s.now()
+	report := TimeReport{ByTag: map[string]time.Duration{}, ByDay: map[string]time.Duration{}}
+	for _, t := range tasks {
+		if t.Trashed() || len(t.TimeLog) == 0 {
+			continue
+		}
+		tracked := t.Tracked(now)
+		report.Total += tracked
+		report.ByTask = append(report.ByTask, TaskTime{Task: t, Duration: tracked})
+		for _, tag := range t.Tags {
+			report.ByTag[tag] += tracked
+		}
+		for _, i := range t.TimeLog {
+			for day, d := range i.byDay(now) {
+				report.ByDay[day] += d
+			}
+		}
+	}
+	slices.SortStableFunc(report.ByTask, func(a, b TaskTime) int {
+		return cmp.Compare(b.Duration, a.Duration)
+	})
+	return report, nil
+}
@@ -0,0 +1,108 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimers(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+
+	for _, desc := range []string{"Write report +work", "Review PR +work +code", "Read book"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := svc.Start(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Start(1); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("expected %v but got %v", ErrTimerRunning, err)
+	}
+
+	// Starting another task stops the first timer
+	now = now.Add(30 * time.Minute)
+	if err := svc.Start(2); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if err := svc.Stop(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Stop(2); !errors.Is(err, ErrTimerNotRunning) {
+		t.Errorf("expected %v but got %v", ErrTimerNotRunning, err)
+	}
+
+	// Parallel timers leave the others going, a running timer counts up to now
+	now = timeMustParse(time.RFC3339, "2026-01-15T10:00:00Z")
+	if err := svc.Start(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Start(1, WithParallelTimers()); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(15 * time.Minute)
+
+	inProgress := StatusInProgress
+	assertIDs(t, "started", func() ([]Task, error) { return svc.List(ListOptions{Status: &inProgress}) }, []int64{1, 2, 3})
+
+	report, err := svc.TimeReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 2*time.Hour {
+		t.Errorf("expected total of 2h but got %v", report.Total)
+	}
+	if len(report.ByTask) != 3 || report.ByTask[0].Task.Id != 2 || report.ByTask[1].Duration != 45*time.Minute {
+		t.Errorf("expected task 2 first and task 1 at 45m but got %+v", report.ByTask)
+	}
+	expectedTags := map[string]time.Duration{"work": time.Hour + 45*time.Minute, "code": time.Hour}
+	for tag, d := range expectedTags {
+		if report.ByTag[tag] != d {
+			t.Errorf("expected %s at %v but got %v", tag, d, report.ByTag[tag])
+		}
+	}
+	if report.ByDay["2026-01-14"] != 90*time.Minute || report.ByDay["2026-01-15"] != 30*time.Minute {
+		t.Errorf("expected 1h30m and 30m per day but got %v", report.ByDay)
+	}
+
+	// Finishing a task stops its timer
+	if err := svc.Mark(3, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := svc.List(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tsk := range tasks {
+		if tsk.Id == 3 && tsk.Running() {
+			t.Errorf("expected the timer on task 3 to stop when done")
+		}
+	}
+}
+
+func TestTimeReportSplitsDays(t *testing.T) {
+	now := timeMustParse(time.RFC3339, "2026-01-14T23:00:00Z")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+	if _, err := svc.Add(Task{Description: "Late night deploy"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Start(1); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := svc.Stop(1); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := svc.TimeReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ByDay["2026-01-14"] != time.Hour || report.ByDay["2026-01-15"] != time.Hour || report.Total != 2*time.Hour {
+		t.Errorf("expected an hour on each day but got %v", report.ByDay)
+	}
+}
@@ -0,0 +1,92 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNegativeAge = errors.New("purge age can't be negative")
+
+// Trashed reports whether the task has been soft deleted
+func (t Task) Trashed() bool {
+	return !t.DeletedAt.IsZero()
+}
+
+// Trash lists every soft deleted task
+func (s TaskService) Trash() ([]Task, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	tasks, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	var trashed []Task
+	for _, t := range tasks {
+		if t.Trashed() {
+			trashed = append(trashed, t)
+		}
+	}
+	return trashed, nil
+}
+
+// Restore takes a task back out of the trash along with the subtasks that
+// were trashed with it. A subtask whose parent is still in the trash is
+// refused, one whose parent was purged comes back as a top level task
+func (s TaskService) Restore(id int64) error {
+	return s.mutate(EventRestore, func(tasks []Task) ([]Task, error) {
+		for i := range tasks {
+			if tasks[i].Id != id || !tasks[i].Trashed() {
+				continue
+			}
+			if parentID := tasks[i].ParentID; parentID != 0 {
+				parent, err := findTask(tasks, parentID)
+				if err != nil {
+					tasks[i].ParentID = 0
+				} else if parent.Trashed() {
+					return nil, fmt.Errorf("%w with id %d, restore %d first", ErrParentTrashed, id, parentID)
+				}
+			}
+			for _, child := range trashedWith(tasks, id, tasks[i].DeletedAt) {
+				tasks[child].DeletedAt = time.Time{}
+			}
+			tasks[i].DeletedAt = time.Time{}
+			return tasks, nil
+		}
+		return nil, fmt.Errorf("%w in trash with id %d", ErrNotFound, id)
+	})
+}
+
+// Purge permanently removes trashed tasks deleted at least olderThan ago and
+// returns how many were removed. Zero purges the whole trash, a negative age
+// is ErrNegativeAge rather than a cutoff in the future
+func (s TaskService) Purge(olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, fmt.Errorf("%w, got %s", ErrNegativeAge, olderThan)
+	}
+	purged := 0
+	err := s.mutate(EventPurge, func(tasks []Task) ([]Task, error) {
+		now := s.now()
+		cutoff := now.Add(-olderThan)
+		removed := map[int64]bool{}
+		kept := tasks[:0]
+		for _, t := range tasks {
+			if t.Trashed() && !t.DeletedAt.After(cutoff) {
+				removed[t.Id] = true
+				continue
+			}
+			kept = append(kept, t)
+		}
+		purged = len(removed)
+		dropDependencies(kept, now, removed)
+		return kept, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
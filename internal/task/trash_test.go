@@ -0,0 +1,85 @@
+package task
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrash(t *testing.T) {
+	start := timeMustParse(time.RFC3339, "2025-12-12T09:00:00Z")
+	now := start
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithTimeFunction(func() time.Time { return now }))
+	list := func() ([]Task, error) { return svc.List(ListOptions{}) }
+
+	for _, desc := range []string{"keep", "trash then restore", "trash then purge"} {
+		if _, err := svc.Add(Task{Description: desc}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(48 * time.Hour)
+	if err := svc.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trashed tasks are hidden from List and can't be changed or deleted again
+	assertIDs(t, "list", list, []int64{1})
+	assertIDs(t, "trash", svc.Trash, []int64{2, 3})
+	if err := svc.Mark(2, StatusDone); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v marking a trashed task but got %v", ErrNotFound, err)
+	}
+	if err := svc.Delete(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v deleting a trashed task but got %v", ErrNotFound, err)
+	}
+
+	if _, err := svc.Purge(-time.Hour); !errors.Is(err, ErrNegativeAge) {
+		t.Errorf("expected %v but got %v", ErrNegativeAge, err)
+	}
+	assertIDs(t, "trash after a negative purge", svc.Trash, []int64{2, 3})
+
+	// Only task 2 has been in the trash longer than a day
+	purged, err := svc.Purge(24 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged != 1 {
+		t.Errorf("expected 1 task purged but got %d", purged)
+	}
+	if err := svc.Restore(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v restoring a purged task but got %v", ErrNotFound, err)
+	}
+	if err := svc.Restore(3); err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, "list after restore", list, []int64{1, 3})
+	assertIDs(t, "trash after restore", svc.Trash, []int64{})
+
+	// Purged ids are never handed out again
+	id, err := svc.Add(Task{Description: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 4 {
+		t.Errorf("expected new task to get ID 4 but got %d", id)
+	}
+}
+
+func assertIDs(t *testing.T, name string, list func() ([]Task, error), expected []int64) {
+	t.Helper()
+	tasks, err := list()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != len(expected) {
+		t.Errorf("%s expected ids %v but got %v", name, expected, tasks)
+		return
+	}
+	for i := range tasks {
+		if tasks[i].Id != expected[i] {
+			t.Errorf("%s expected ids %v but got %v", name, expected, tasks)
+		}
+	}
+}
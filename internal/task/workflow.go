@@ -0,0 +1,227 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnknownStatus     = errors.New("unknown status")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrInvalidWorkflow   = errors.New("invalid workflow")
+)
+
+// Workflow names the statuses a task can be in and which moves between them
+// are allowed. The built in todo, in-progress and done always keep ids 0, 1
+// and 2 so existing task files read the same. Custom statuses get an id
+// derived from their name, see customStatusID
+type Workflow struct {
+	// statuses lists every status in workflow order
+	statuses []Status
+	names    map[Status]string
+	// transitions lists the statuses each status may move to, nil allows any
+	// move
+	transitions map[Status][]Status
+}
+
+// DefaultWorkflow has just the built in statuses and allows any move
+func DefaultWorkflow() Workflow {
+	w := Workflow{names: map[Status]string{}}
+	for _, s := range []Status{StatusTodo, StatusInProgress, StatusDone} {
+		w.statuses = append(w.statuses, s)
+		w.names[s] = s.String()
+	}
+	return w
+}
+
+// customStatusID derives the id of a custom status from its name, so adding,
+// removing or reordering statuses in the config never changes what the
+// statuses of stored tasks mean
+func customStatusID(name string) Status {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	// Kept within 30 bits so the id fits an int everywhere, past the built ins
+	return Status(h.Sum32()>>2) + 3
+}
+
+// NewWorkflow builds a workflow from status names and, optionally, the moves
+// allowed out of each status. Built in statuses may be listed but don't have
+// to be. With no transitions any move is allowed, otherwise a status missing
+// from transitions can't be left
+func NewWorkflow(statuses []string, transitions map[string][]string) (Workflow, error) {
+	w := DefaultWorkflow()
+	for _, name := range statuses {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || strings.ContainsFunc(name, isSpaceOrPlus) {
+			return Workflow{}, fmt.Errorf("%w: status %q must be a single word", ErrInvalidWorkflow, name)
+		}
+		// list takes statuses and views as the same bare word
+		if slices.Contains(Views(), View(name)) {
+			return Workflow{}, fmt.Errorf("%w: status %q has the name of a view", ErrInvalidWorkflow, name)
+		}
+		if _, err := w.Parse(name); err == nil {
+			if _, err := DefaultWorkflow().Parse(name); err == nil {
+				continue
+			}
+			return Workflow{}, fmt.Errorf("%w: status %q is listed twice", ErrInvalidWorkflow, name)
+		}
+		id := customStatusID(name)
+		if other, ok := w.names[id]; ok {
+			return Workflow{}, fmt.Errorf("%w: statuses %q and %q can't be told apart, rename one", ErrInvalidWorkflow, other, name)
+		}
+		w.statuses = append(w.statuses, id)
+		w.names[id] = name
+	}
+	w.statuses = workflowOrder(statuses, w)
+
+	if len(transitions) == 0 {
+		return w, nil
+	}
+	w.transitions = map[Status][]Status{}
+	for from, targets := range transitions {
+		fromStatus, err := w.Parse(from)
+		if err != nil {
+			return Workflow{}, fmt.Errorf("%w: transition from %w", ErrInvalidWorkflow, err)
+		}
+		for _, to := range targets {
+			toStatus, err := w.Parse(to)
+			if err != nil {
+				return Workflow{}, fmt.Errorf("%w: transition from %s to %w", ErrInvalidWorkflow, from, err)
+			}
+			if !slices.Contains(w.transitions[fromStatus], toStatus) {
+				w.transitions[fromStatus] = append(w.transitions[fromStatus], toStatus)
+			}
+		}
+		slices.Sort(w.transitions[fromStatus])
+	}
+	return w, nil
+}
+
+// workflowOrder lists the statuses in the order they were given. Built ins
+// that weren't listed keep their natural place: todo first, in-progress right
+// after it and done last
+func workflowOrder(listed []string, w Workflow) []Status {
+	var order []Status
+	for _, name := range listed {
+		if s, err := w.Parse(name); err == nil && !slices.Contains(order, s) {
+			order = append(order, s)
+		}
+	}
+	if !slices.Contains(order, StatusTodo) {
+		order = slices.Insert(order, 0, StatusTodo)
+	}
+	if !slices.Contains(order, StatusInProgress) {
+		order = slices.Insert(order, slices.Index(order, StatusTodo)+1, StatusInProgress)
+	}
+	if !slices.Contains(order, StatusDone) {
+		order = append(order, StatusDone)
+	}
+	return order
+}
+
+func isSpaceOrPlus(r rune) bool {
+	return r == ' ' || r == '\t' || r == '+'
+}
+
+// Statuses lists every status in the order the workflow gives them
+func (w Workflow) Statuses() []Status {
+	return slices.Clone(w.statuses)
+}
+
+// Known reports whether the workflow has the status, tasks can be left on a
+// status that has since been removed from the config
+func (w Workflow) Known(s Status) bool {
+	_, ok := w.names[s]
+	return ok
+}
+
+// rank is the place of a status in the workflow order, unknown statuses come
+// after every known one
+func (w Workflow) rank(s Status) int {
+	if i := slices.Index(w.statuses, s); i >= 0 {
+		return i
+	}
+	return len(w.statuses)
+}
+
+// Name of a status, statuses the workflow doesn't know show up as unknown with
+// their id
+func (w Workflow) Name(s Status) string {
+	if name, ok := w.names[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", s)
+}
+
+// Parse looks a status up by name, ignoring case
+func (w Workflow) Parse(name string) (Status, error) {
+	names := make([]string, len(w.statuses))
+	for i, s := range w.statuses {
+		if strings.EqualFold(w.names[s], strings.TrimSpace(name)) {
+			return s, nil
+		}
+		names[i] = w.names[s]
+	}
+	return 0, fmt.Errorf("%w %q, expected one of %s", ErrUnknownStatus, name, strings.Join(names, ", "))
+}
+
+// unknownStatuses explains which tasks sit on a status the workflow no longer
+// has, one error per status
+func (w Workflow) unknownStatuses(tasks []Task) []error {
+	stranded := map[Status][]int64{}
+	for _, t := range tasks {
+		if !w.Known(t.Status) {
+			stranded[t.Status] = append(stranded[t.Status], t.Id)
+		}
+	}
+	var errs []error
+	for _, status := range slices.Sorted(maps.Keys(stranded)) {
+		errs = append(errs, fmt.Errorf("%w %d on tasks %s, it is not in the workflow, mark them with a status that is", ErrUnknownStatus, status, joinIDs(stranded[status])))
+	}
+	return errs
+}
+
+// Next lists the statuses a task may move to from the given one
+func (w Workflow) Next(from Status) []Status {
+	if w.transitions == nil {
+		return slices.DeleteFunc(w.Statuses(), func(s Status) bool { return s == from })
+	}
+	return slices.Clone(w.transitions[from])
+}
+
+// checkTransition explains why a task can't move from one status to another.
+// Staying put is always allowed
+func (w Workflow) checkTransition(id int64, from, to Status) error {
+	if !w.Known(to) {
+		return fmt.Errorf("%w %d", ErrUnknownStatus, to)
+	}
+	// A task stranded on a removed status may move anywhere
+	if from == to || !w.Known(from) || w.transitions == nil || slices.Contains(w.transitions[from], to) {
+		return nil
+	}
+
+	allowed := w.Next(from)
+	if len(allowed) == 0 {
+		return fmt.Errorf("%w from %s to %s with id %d, %s is final", ErrInvalidTransition, w.Name(from), w.Name(to), id, w.Name(from))
+	}
+	names := make([]string, len(allowed))
+	for i, s := range allowed {
+		names[i] = w.Name(s)
+	}
+	return fmt.Errorf("%w from %s to %s with id %d, %s can only move to %s", ErrInvalidTransition, w.Name(from), w.Name(to), id, w.Name(from), strings.Join(names, ", "))
+}
+
+// WithWorkflow replaces the built in statuses and their free transitions
+func WithWorkflow(w Workflow) TaskServiceOption {
+	return func(svc *TaskService) {
+		svc.workflow = w
+	}
+}
+
+func (s TaskService) Workflow() Workflow {
+	return s.workflow
+}
@@ -0,0 +1,183 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWorkflow(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []string
+		transitions   map[string][]string
+		expected      []string
+		expectedError error
+	}{
+		{
+			name:     "builtinsOnly",
+			expected: []string{"todo", "in-progress", "done"},
+		},
+		{
+			name:     "configOrder",
+			statuses: []string{"todo", "in-progress", "Review", "on-hold", "done"},
+			expected: []string{"todo", "in-progress", "review", "on-hold", "done"},
+		},
+		{
+			name:     "builtinsFillIn",
+			statuses: []string{"review", "in-progress"},
+			expected: []string{"todo", "review", "in-progress", "done"},
+		},
+		{
+			name:          "viewName",
+			statuses:      []string{"Blocked"},
+			expectedError: ErrInvalidWorkflow,
+		},
+		{
+			name:          "duplicate",
+			statuses:      []string{"review", "review"},
+			expectedError: ErrInvalidWorkflow,
+		},
+		{
+			name:          "spaces",
+			statuses:      []string{"in review"},
+			expectedError: ErrInvalidWorkflow,
+		},
+		{
+			name:          "unknownTransition",
+			statuses:      []string{"review"},
+			transitions:   map[string][]string{"review": {"shipped"}},
+			expectedError: ErrUnknownStatus,
+		},
+	}
+
+	for _, tst := range tests {
+		w, err := NewWorkflow(tst.statuses, tst.transitions)
+		if tst.expectedError != nil {
+			if !errors.Is(err, tst.expectedError) {
+				t.Errorf("%s expected %v but got %v", tst.name, tst.expectedError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tst.name, err)
+			continue
+		}
+		var names []string
+		for _, s := range w.Statuses() {
+			names = append(names, w.Name(s))
+		}
+		if strings.Join(names, ",") != strings.Join(tst.expected, ",") {
+			t.Errorf("%s expected %v but got %v", tst.name, tst.expected, names)
+		}
+	}
+}
+
+func TestMarkFollowsWorkflow(t *testing.T) {
+	w, err := NewWorkflow([]string{"review"}, map[string][]string{
+		"todo":        {"in-progress"},
+		"in-progress": {"review", "todo"},
+		"review":      {"done", "in-progress"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	review, _ := w.Parse("review")
+	svc := NewTaskService(WithStore(NewMemoryStore()), WithWorkflow(w), WithTimeFunction(func() time.Time {
+		return timeMustParse(time.RFC3339, "2026-01-14T09:00:00Z")
+	}))
+
+	id, err := svc.Add(Task{Description: "Ship feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	childID, err := svc.Add(Task{Description: "Write tests", ParentID: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = svc.Mark(id, StatusDone)
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected %v but got %v", ErrInvalidTransition, err)
+	}
+	if expected := "from todo to done with id 1, todo can only move to in-progress"; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to explain %q but got %q", expected, err)
+	}
+	if err := svc.Mark(id, Status(9)); !errors.Is(err, ErrUnknownStatus) {
+		t.Errorf("expected %v but got %v", ErrUnknownStatus, err)
+	}
+
+	for _, status := range []Status{StatusInProgress, review} {
+		if err := svc.Mark(id, status); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Cascading to a subtask follows the rules too
+	if err := svc.Mark(id, StatusDone, WithCascade()); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("expected the todo subtask to be refused but got %v", err)
+	}
+	for _, status := range []Status{StatusInProgress, review, StatusDone} {
+		if err := svc.Mark(childID, status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Mark(id, StatusDone); err != nil {
+		t.Fatal(err)
+	}
+
+	// Done has no moves out of it
+	if err := svc.Mark(id, StatusTodo); err == nil || !strings.Contains(err.Error(), "done is final") {
+		t.Errorf("expected done to be final but got %v", err)
+	}
+
+	history, err := svc.History(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := history[2]; c.From != "in-progress" || c.To != "review" {
+		t.Errorf("expected the custom status to be recorded by name but got %+v", c)
+	}
+}
+
+func TestCustomStatusesKeepTheirIDs(t *testing.T) {
+	before, err := NewWorkflow([]string{"review", "qa"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	qa, _ := before.Parse("qa")
+	review, _ := before.Parse("review")
+	store := NewMemoryStore(Task{Id: 1, Description: "Check the release", Status: qa}, Task{Id: 2, Description: "Read the diff", Status: review})
+
+	// Reordering keeps the meaning of stored statuses
+	after, err := NewWorkflow([]string{"qa", "review"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewTaskService(WithStore(store), WithWorkflow(after), WithTimeFunction(time.Now))
+	got, err := svc.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := after.Name(got.Status); name != "qa" {
+		t.Errorf("expected qa after reordering but got %s", name)
+	}
+	if warnings := svc.Warnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings but got %v", warnings)
+	}
+
+	// Removing a status leaves its tasks reported rather than renamed
+	removed, err := NewWorkflow([]string{"qa"}, map[string][]string{"todo": {"done"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc = NewTaskService(WithStore(store), WithWorkflow(removed), WithTimeFunction(time.Now))
+	warnings := svc.Warnings()
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrUnknownStatus) || !strings.Contains(warnings[0].Error(), "on tasks 2") {
+		t.Fatalf("expected a warning about task 2 but got %v", warnings)
+	}
+	if err := svc.Mark(2, StatusTodo); err != nil {
+		t.Errorf("expected a task on a removed status to move anywhere but got %v", err)
+	}
+}